@@ -0,0 +1,53 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// AgentTransfer simulates the agent who took a call transferring the customer (such as with a quick connect) to the named flow.
+// The flow is the one the quick connect runs, such as a transfer to queue flow.
+//
+// As in Connect, the transfer creates a new contact, which is returned. Its ContactId is new, its PreviousContactId is the call's ContactId,
+// its InitialContactId is that of the call and its InitiationMethod is TRANSFER. It keeps the call's attributes, customer and channel.
+// It emits a ContactCreatedEvent as it starts.
+//
+// The call must have ended, as it does once the flow has transferred it to a queue for an agent.
+// If subscribe is not nil, it is called with the new contact before it starts running, so that events can be subscribed to without missing any.
+func (c *Call) AgentTransfer(flowName string, subscribe func(transfer *Call)) (*Call, error) {
+	select {
+	case <-c.ended:
+	default:
+		return nil, errors.New("cannot transfer a call that has not reached an agent")
+	}
+	f, ok := c.sc.flows[flowName]
+	if !ok {
+		return nil, fmt.Errorf("flow not found: %s. Load the flow with LoadFlow before calling this method", flowName)
+	}
+	c.stateMutex.RLock()
+	t := newCall(CallConfig{
+		SourceNumber: c.System[flow.SystemCustomerNumber],
+		DestNumber:   c.System[flow.SystemDialedNumber],
+		Time:         c.now(),
+		Attributes:   c.ContactData,
+		Channel:      c.System[flow.SystemChannel],
+		InstanceARN:  c.System[flow.SystemInstanceARN],
+		LanguageCode: c.System[flow.SystemLanguageCode],
+		CustomerName: c.displayName,
+	}, c.sc)
+	t.System[flow.SystemPreviousContactID] = c.System[flow.SystemContactID]
+	t.System[flow.SystemInitialContactID] = c.System[flow.SystemInitialContactID]
+	t.System[flow.SystemInitiationMethod] = "TRANSFER"
+	t.System[flow.SystemTextToSpeechVoice] = c.System[flow.SystemTextToSpeechVoice]
+	c.stateMutex.RUnlock()
+	t.ctx = c.ctx
+	t.announce = true
+
+	if subscribe != nil {
+		subscribe(t)
+	}
+	t.start(c.sc, f.Start)
+	return t, nil
+}
@@ -0,0 +1,91 @@
+package simulator_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleBargeIn = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000e-000000000001","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000e-000000000002"}],"parameters":[{"name":"Text","value":"Welcome.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000e-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-000e-000000000003"},{"condition":"Evaluate","conditionType":"Equals","conditionValue":"2","transition":"00000000-0000-4000-000e-000000000004"},{"condition":"Timeout","transition":"00000000-0000-4000-000e-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-000e-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-000e-000000000009"}],"parameters":[{"name":"Text","value":"Press 1 to hear your balance, or 2 to make a payment."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-000e-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000e-000000000009"}],"parameters":[{"name":"Text","value":"Your balance is 10 pounds.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000e-000000000004","type":"StoreUserInput","branches":[{"condition":"Success","transition":"00000000-0000-4000-000e-000000000006"},{"condition":"Error","transition":"00000000-0000-4000-000e-000000000009"}],"parameters":[{"name":"Text","value":"Please enter your card number, followed by the hash key."},{"name":"TextToSpeechType","value":"text"},{"name":"CustomerInputType","value":"Custom"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":20},{"name":"EncryptEntry","value":false},{"name":"DisableCancel","value":false}]},
+        {"id":"00000000-0000-4000-000e-000000000006","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-000e-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-000e-000000000009"}],"parameters":[{"name":"Attribute","value":"Stored customer input","key":"card","namespace":"System"}]},
+        {"id":"00000000-0000-4000-000e-000000000005","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000e-000000000009"}],"parameters":[{"name":"Text","value":"You entered $.Attributes.card.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000e-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000e-000000000001",
+    "metadata":{"name":"Sample barge-in flow","description":"","type":"contactFlow"}
+}`
+
+func TestBargeIn(t *testing.T) {
+	sim := newTestSimulator(t, "Sample barge-in flow", sampleBargeIn)
+	menu := "Press 1 to hear your balance, or 2 to make a payment."
+
+	t.Run("interrupt menu", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("Welcome.")
+		expect.Caller().ToInterrupt("1")
+		expect.Prompt().ToBeInterrupted(menu)
+		expect.Prompt().ToEqual("Your balance is 10 pounds.")
+	})
+	t.Run("listen to menu", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().Never().ToBeInterrupted(menu)
+		expect.Prompt().ToEqual(menu)
+		expect.Caller().ToPress('1')
+		expect.Prompt().ToEqual("Your balance is 10 pounds.")
+	})
+	t.Run("interrupt entry", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Caller().ToInterrupt("2")
+		expect.Caller().ToInterrupt("4111#")
+		expect.Prompt().ToBeInterrupted("Please enter your card number, followed by the hash key.")
+		expect.Prompt().ToEqual("You entered 4111.")
+	})
+	t.Run("raw channels", func(t *testing.T) {
+		// The menu is 12 words long, so takes 4.8 seconds to play.
+		length := 4800 * time.Millisecond
+		testCases := []struct {
+			desc      string
+			after     time.Duration
+			expPlayed time.Duration
+		}{
+			{desc: "half way by default", expPlayed: length / 2},
+			{desc: "after a set time", after: time.Second, expPlayed: time.Second},
+			{desc: "after the end", after: time.Minute, expPlayed: length},
+		}
+		for _, tC := range testCases {
+			t.Run(tC.desc, func(t *testing.T) {
+				call := startTestCall(t, sim, CallConfig{BargeInAfter: tC.after})
+				evts := make(chan event.Event, 64)
+				call.Subscribe(evts)
+				if p := <-call.Caller.O; p != "Welcome." {
+					t.Fatalf("expected welcome prompt but got '%s'", p)
+				}
+				call.Caller.I <- '1'
+				if p := <-call.Caller.O; p != "Your balance is 10 pounds." {
+					t.Errorf("expected the menu to be cut short but got '%s'", p)
+				}
+				for evt := range evts {
+					if e, ok := evt.(event.PromptInterruptedEvent); ok {
+						exp := event.PromptInterruptedEvent{Text: menu, Played: tC.expPlayed, Length: length}
+						if e != exp {
+							t.Errorf("expected %+v but got %+v", exp, e)
+						}
+						return
+					}
+				}
+				t.Error("expected a prompt interrupted event but got none")
+			})
+		}
+	})
+}
@@ -1,15 +1,18 @@
 package simulator
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/edwardbrowncross/amazon-connect-simulator/event"
 	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
 	"github.com/edwardbrowncross/amazon-connect-simulator/module"
-	"github.com/google/uuid"
 )
 
 // Call is used to interact with an ongoing call.
@@ -19,19 +22,69 @@ type Call struct {
 		O <-chan string
 		// Input (keypad).
 		I chan<- rune
+		// Input (speech on calls, typed messages on chats).
+		T chan<- string
 	}
-	o           chan<- string
-	i           <-chan rune
-	Err         error
-	evts        []chan<- event.Event
-	kill        chan<- interface{}
-	evtsMutex   sync.Mutex
+	o                chan<- string
+	i                <-chan rune
+	t                <-chan string
+	pending          []string
+	pendingKeys      []rune
+	bargeInAfter     time.Duration
+	displayName      string
+	task             *taskDetails
+	Err              error
+	kill             chan interface{}
+	killOnce         sync.Once
+	ended            chan struct{}
+	ctx              context.Context
+	sc               *simulatorConnector
+	hangup           chan interface{}
+	hangupOnce       sync.Once
+	hooks            map[flow.EventHook]string
+	logging          bool
+	recording        *ContactRecordRecording
+	priority         int
+	ageAdjust        time.Duration
+	disconnectReason event.DisconnectReason
+	// queue is the name of the queue the call was transferred to, if any. It is cleared when the call leaves the queue.
+	queue string
+	// announce is set on contacts created from another call, which emit a ContactCreatedEvent as they start.
+	announce bool
+	// evtsMutex is held while an event is written to subscribers. subsMutex guards the list of subscribers.
+	evtsMutex  sync.Mutex
+	subsMutex  sync.Mutex
+	evts       []*subscriber
+	evtsClosed bool
+	// stateMutex guards the attribute namespaces, the current block and the settings made by blocks, which are written by the running call.
+	stateMutex sync.RWMutex
+	module     *flow.Module
+	flowName   string
+	// remaining are the flows to run after the current one.
+	remaining []flow.ModuleID
+	// elapsed is the simulated time the call has spent in flows, which passes as prompts play and as the caller is waited for.
+	elapsed time.Duration
+	// External, ContactData and System are the three namespaces of attributes.
+	// They are written to as the call runs, so should only be read directly once it has ended. Use Snapshot while it is running.
 	External    map[string]string
 	ContactData map[string]string
 	System      map[flow.SystemKey]string
 	Time        time.Time
 }
 
+// CallSnapshot is a copy of the state of a call at one moment.
+type CallSnapshot struct {
+	External    map[string]string
+	ContactData map[string]string
+	System      map[flow.SystemKey]string
+	// Module is the block being run, or nil if the call is between blocks or has ended.
+	Module *flow.Module
+	// Flow is the name of the flow the block is in.
+	Flow string
+	// Time is the time within the call.
+	Time time.Time
+}
+
 // CallConfig is data unique to this particular call.
 type CallConfig struct {
 	// SourceNumber is the customer's phone number.
@@ -40,101 +93,395 @@ type CallConfig struct {
 	DestNumber string
 	// Time is the time the customer is phoning (for in-hours check).
 	Time time.Time
+	// Attributes are contact attributes set before the flow starts, as they would be by StartOutboundVoiceContact or by a transfer from another instance.
+	Attributes map[string]string
+	// Channel is the channel the contact comes in on: VOICE (the default) or CHAT.
+	// A chat takes whole messages from Caller.T in the same way as one started with StartChat.
+	Channel string
+	// InstanceARN is the ARN of the instance taking the call. By default, it is the ARN of the loaded instance configuration.
+	InstanceARN string
+	// LanguageCode is the language of the contact, such as en-GB. By default, it is en-US.
+	LanguageCode string
+	// CustomerName is the customer's name. It is the display name given to messages the customer sends on a chat.
+	CustomerName string
+	// BargeInAfter is how much of a prompt has played when a key the caller presses during it cuts it short.
+	// By default, the caller interrupts half way through the prompt.
+	BargeInAfter time.Duration
+	// System sets values in the System namespace, replacing those the call would otherwise start with.
+	// It is applied after all other configuration, so can set anything not covered above (such as InitiationMethod).
+	System map[flow.SystemKey]string
 }
 
 // New is used by the simulator to create a new call.
-func newCall(conf CallConfig, sc *simulatorConnector, start flow.ModuleID) *Call {
+// The call does not run until start is called.
+func newCall(conf CallConfig, sc *simulatorConnector) *Call {
 	out := make(chan string)
 	in := make(chan rune)
+	text := make(chan string)
 	kill := make(chan interface{})
 	c := Call{
 		Caller: struct {
 			O <-chan string
 			I chan<- rune
-		}{out, in},
+			T chan<- string
+		}{out, in, text},
 		o:           out,
 		i:           in,
+		t:           text,
 		kill:        kill,
-		evtsMutex:   sync.Mutex{},
-		evts:        make([]chan<- event.Event, 0),
+		ended:       make(chan struct{}),
+		ctx:         context.Background(),
+		sc:          sc,
+		hangup:      make(chan interface{}),
+		hooks:       map[flow.EventHook]string{},
+		priority:    defaultRoutingPriority,
+		evts:        make([]*subscriber, 0),
 		External:    map[string]string{},
 		ContactData: map[string]string{},
 		System:      map[flow.SystemKey]string{},
 		Time:        conf.Time,
 	}
 	if c.Time.IsZero() {
-		c.Time = time.Now()
-	}
-	var contactID string
-	if uuid, err := uuid.NewUUID(); err == nil {
-		contactID = uuid.String()
+		c.Time = sc.clock()
 	}
+	contactID := sc.newID()
 	c.System[flow.SystemCustomerNumber] = conf.SourceNumber
 	c.System[flow.SystemDialedNumber] = conf.DestNumber
-	c.System[flow.SystemChannel] = "VOICE"
+	c.System[flow.SystemChannel] = flow.ChannelVoice
+	if conf.Channel != "" {
+		c.System[flow.SystemChannel] = conf.Channel
+	}
 	c.System[flow.SystemInitiationMethod] = "INBOUND"
 	c.System[flow.SystemContactID] = contactID
 	c.System[flow.SystemPreviousContactID] = contactID
 	c.System[flow.SystemInitialContactID] = contactID
 	c.System[flow.SystemTextToSpeechVoice] = "Joanna"
-	go c.run(start, callConnector{&c, sc}, kill)
+	c.System[flow.SystemLanguageCode] = "en-US"
+	if conf.LanguageCode != "" {
+		c.System[flow.SystemLanguageCode] = conf.LanguageCode
+	}
+	if sc.instance.arn != "" {
+		c.System[flow.SystemInstanceARN] = sc.instance.arn
+	}
+	if conf.InstanceARN != "" {
+		c.System[flow.SystemInstanceARN] = conf.InstanceARN
+	}
+	c.displayName = conf.CustomerName
+	c.bargeInAfter = conf.BargeInAfter
+	for k, v := range conf.Attributes {
+		c.ContactData[k] = v
+	}
+	for k, v := range conf.System {
+		c.System[k] = v
+	}
 	return &c
 }
 
-func (c *Call) run(start flow.ModuleID, cs callConnector, kill <-chan interface{}) {
+// start runs the given flows one after the other in a new go routine.
+// A flow is followed by the next only if it comes to an end without the caller hanging up. If no flows are given, the call was never answered.
+// If the call's context is cancelled before the call ends, the call is terminated.
+func (c *Call) start(sc *simulatorConnector, flows ...flow.ModuleID) {
+	if done := c.ctx.Done(); done != nil {
+		go func() {
+			select {
+			case <-done:
+				c.Terminate()
+			case <-c.ended:
+			}
+		}()
+	}
+	go c.run(flows, callConnector{c, sc}, c.kill)
+}
+
+func (c *Call) run(flows []flow.ModuleID, cs callConnector, kill <-chan interface{}) {
 	var next *flow.ModuleID
 	var err error
-	next = &start
+	hangup := c.hangup
+	reason := event.DisconnectReason(event.DisconnectContactFlow)
+	if len(flows) == 0 {
+		reason = event.DisconnectOutboundAttemptFailed
+	} else {
+		next, flows = &flows[0], flows[1:]
+	}
+	guards := newLoopTracker(cs.guards)
+	if c.announce {
+		c.emit(event.ContactCreatedEvent{
+			ContactID:         c.System[flow.SystemContactID],
+			PreviousContactID: c.System[flow.SystemPreviousContactID],
+			InitialContactID:  c.System[flow.SystemInitialContactID],
+			InitiationMethod:  c.System[flow.SystemInitiationMethod],
+			Channel:           c.System[flow.SystemChannel],
+		})
+	}
 loop:
 	for next != nil && err == nil {
 		select {
 		case _, ok := <-kill:
 			if !ok {
+				if reason != event.DisconnectCustomer {
+					reason = event.DisconnectTelecom
+				}
+				err = c.ctx.Err()
 				break loop
 			}
+		case <-hangup:
+			// The caller has gone. Run the disconnect flow if one was set, otherwise we are done.
+			hangup = nil
+			reason = event.DisconnectCustomer
+			next = nil
+			if name, ok := c.hooks[flow.HookDisconnect]; ok {
+				next = cs.GetFlowStart(name)
+			}
 		default:
 			m := cs.GetModule(*next)
 			if m == nil {
-				err = fmt.Errorf("missing module: %v", *next)
+				missing := &module.Error{
+					Kind:   module.ErrMissingModule,
+					Target: *next,
+					Flow:   c.flowName,
+					Err:    fmt.Errorf("missing module: %v", *next),
+				}
+				if c.module != nil {
+					missing.ModuleID = c.module.ID
+					missing.ModuleType = c.module.Type
+				}
+				err = missing
+				break loop
 			}
+			if loopErr := guards.visit(m.ID, c.elapsed); loopErr != nil {
+				loopErr.Flow = cs.GetModuleFlowName(m.ID)
+				err = loopErr
+				break loop
+			}
+			c.stateMutex.Lock()
+			c.module = m
+			c.flowName = cs.GetModuleFlowName(m.ID)
+			c.remaining = flows
+			c.stateMutex.Unlock()
 			c.emit(event.NewModuleEvent(*m))
+			var params map[string]interface{}
+			if c.logging || m.Type == flow.ModuleSetLoggingBehavior {
+				// Parameters are logged with the values they had when the block started.
+				params = module.ResolveParameters(m.Parameters, &cs)
+			}
 			next, err = module.MakeRunner(*m).Run(&cs)
+			if err != nil {
+				err = blockError(err, *m, c.flowName)
+			}
+			if hangup != nil && c.hungUp() {
+				// The caller hung up while the block ran. The branch it took (usually Timeout) was never really followed.
+				continue
+			}
+			var result flow.ModuleBranchCondition
 			if next != nil {
-				c.emit(event.NewBranchEvent(*m, *next))
+				evt := event.NewBranchEvent(*m, *next)
+				result = evt.Label
+				c.emit(evt)
+			}
+			if c.logging {
+				if logErr := cs.log(*m, params, result); logErr != nil && err == nil {
+					err = fmt.Errorf("failed to write flow log: %w", logErr)
+				}
+			}
+			if next == nil && err == nil && hangup != nil && len(flows) > 0 {
+				next, flows = &flows[0], flows[1:]
 			}
 		}
 	}
-	c.emit(event.DisconnectEvent{})
+	c.stateMutex.Lock()
+	c.module = nil
+	c.flowName = ""
+	c.disconnectReason = reason
+	c.stateMutex.Unlock()
+	c.emit(event.DisconnectEvent{Reason: reason})
 	c.Err = err
+	close(c.ended)
 	close(c.o)
+	// A caller who hangs up (or is cut off) after being queued no longer waits for an agent.
+	if c.hungUp() || c.killed() {
+		c.leaveQueue()
+	}
 	c.evtsMutex.Lock()
-	for _, ch := range c.evts {
-		close(ch)
+	c.subsMutex.Lock()
+	for _, sub := range c.evts {
+		sub.close()
 	}
+	c.evts = nil
+	c.evtsClosed = true
+	c.subsMutex.Unlock()
 	c.evtsMutex.Unlock()
 }
 
+// blockError gives the error returned by a block as a *module.Error that names the block and its flow.
+func blockError(err error, m flow.Module, flowName string) error {
+	var blockErr *module.Error
+	if !errors.As(err, &blockErr) {
+		blockErr = &module.Error{Err: err}
+		err = blockErr
+	}
+	blockErr.ModuleID = m.ID
+	blockErr.ModuleType = m.Type
+	blockErr.Flow = flowName
+	return err
+}
+
 func (c *Call) emit(event event.Event) {
 	c.evtsMutex.Lock()
-	for _, evt := range c.evts {
-		evt <- event
+	c.subsMutex.Lock()
+	subs := c.evts
+	c.subsMutex.Unlock()
+	for _, sub := range subs {
+		if sub.wants(event) {
+			sub.send(event)
+		}
 	}
 	c.evtsMutex.Unlock()
 }
 
 // Subscribe registers to receive structured events from the call.
-// It takes a channel which events will be written to.
-// The call will be blocked if the events cannot be written to the channel.
-func (c *Call) Subscribe(events chan<- event.Event) {
+// It takes a channel which events will be written to. The channel is closed when the call ends.
+// By default, the call will be blocked if the events cannot be written to the channel. Use the Buffered or DropOldest options to stop a slow subscriber holding up the call.
+func (c *Call) Subscribe(events chan<- event.Event, opts ...SubscribeOption) *Subscription {
+	sub := newSubscriber(events, opts)
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+	if c.evtsClosed {
+		sub.close()
+	} else {
+		c.evts = append(c.evts, sub)
+	}
+	return &Subscription{c, sub}
+}
+
+// Unsubscribe stops events being written to a channel given to Subscribe, and closes it.
+// Events still held for the channel (see Buffered) are dropped.
+// It does nothing if the channel is not subscribed.
+func (c *Call) Unsubscribe(events chan<- event.Event) {
+	c.subsMutex.Lock()
+	var sub *subscriber
+	for i, s := range c.evts {
+		if s.ch == events {
+			sub = s
+			c.evts = append(c.evts[:i:i], c.evts[i+1:]...)
+			break
+		}
+	}
+	c.subsMutex.Unlock()
+	if sub == nil {
+		return
+	}
+	// Give up on any event being written to the channel, then close it once no more are being written.
+	sub.stop()
 	c.evtsMutex.Lock()
-	c.evts = append(c.evts, events)
+	sub.close()
 	c.evtsMutex.Unlock()
 }
 
 // Terminate ends an ongoing call.
-// If the call has already ended, it does nothing.
+// If the call has already ended, it does nothing, except to take the contact out of any queue it is waiting in.
+// It does not run the disconnect flow. To simulate the caller putting the phone down, use Hangup.
 func (c *Call) Terminate() {
-	close(c.kill)
+	c.killOnce.Do(func() {
+		close(c.kill)
+	})
+	if c.hasEnded() {
+		c.leaveQueue()
+	}
+}
+
+// killed returns true if the call has been terminated.
+func (c *Call) killed() bool {
+	select {
+	case <-c.kill:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasEnded returns true once the call's flows have stopped running.
+func (c *Call) hasEnded() bool {
+	select {
+	case <-c.ended:
+		return true
+	default:
+		return false
+	}
+}
+
+// leaveQueue takes the call out of the queue it was transferred to, if it is still waiting there.
+func (c *Call) leaveQueue() {
+	c.stateMutex.Lock()
+	queue := c.queue
+	c.queue = ""
+	c.stateMutex.Unlock()
+	if queue != "" {
+		c.sc.queues.remove(queue, c.System[flow.SystemContactID])
+	}
+}
+
+// Snapshot gives a copy of the call's attributes, the block it is running and the time within the call.
+// It is safe to use while the call is running.
+func (c *Call) Snapshot() CallSnapshot {
+	c.stateMutex.RLock()
+	defer c.stateMutex.RUnlock()
+	snap := CallSnapshot{
+		External:    make(map[string]string, len(c.External)),
+		ContactData: make(map[string]string, len(c.ContactData)),
+		System:      make(map[flow.SystemKey]string, len(c.System)),
+		Flow:        c.flowName,
+		Time:        c.now(),
+	}
+	for k, v := range c.External {
+		snap.External[k] = v
+	}
+	for k, v := range c.ContactData {
+		snap.ContactData[k] = v
+	}
+	for k, v := range c.System {
+		snap.System[k] = v
+	}
+	if c.module != nil {
+		m := *c.module
+		snap.Module = &m
+	}
+	return snap
+}
+
+// now gives the current time within the call.
+// The call's clock starts at the time given in the CallConfig and moves on with the simulated time spent in flows.
+func (c *Call) now() time.Time {
+	return c.Time.Add(c.elapsed)
+}
+
+// advance moves on the simulated time the call has spent in flows.
+func (c *Call) advance(d time.Duration) {
+	c.stateMutex.Lock()
+	c.elapsed += d
+	c.stateMutex.Unlock()
+}
+
+// Hangup simulates the caller ending the call.
+// If a disconnect flow has been set, it is run before the call ends. Prompts played by it are not heard and any input times out.
+// A caller waiting in a queue is taken out of it.
+// Calling Hangup more than once has no further effect.
+func (c *Call) Hangup() {
+	c.hangupOnce.Do(func() {
+		close(c.hangup)
+	})
+	if c.hasEnded() {
+		c.leaveQueue()
+	}
+}
+
+// hungUp returns true if the caller has hung up.
+func (c *Call) hungUp() bool {
+	select {
+	case <-c.hangup:
+		return true
+	default:
+		return false
+	}
 }
 
 // callConnector exposes methods for modules to interact with the ongoing call.
@@ -144,51 +491,210 @@ type callConnector struct {
 }
 
 func (s *callConnector) Send(msg string, ssml bool) {
-	s.emit(event.PromptEvent{
-		Text:  msg,
-		SSML:  ssml,
-		Voice: *s.GetSystem(flow.SystemTextToSpeechVoice),
-	})
-	s.o <- msg
+	s.send(msg, ssml, false, nil)
+}
+
+// SendInterruptible plays a prompt that the caller can cut short by pressing a key (barge-in).
+// A key pressed before the prompt is taken from the output channel interrupts it, and is kept as the first key of the input that follows.
+// Prompts can only be interrupted on voice calls.
+func (s *callConnector) SendInterruptible(msg string, ssml bool) {
+	s.send(msg, ssml, s.System[flow.SystemChannel] == flow.ChannelVoice, nil)
+}
+
+// SendOptions plays a prompt that asks the caller to choose one of the given options.
+// On chat, the options are shown as a list below the prompt, unless the prompt is an interactive message with options of its own.
+// On voice, the options are not read out and the prompt can be interrupted, as with SendInterruptible.
+func (s *callConnector) SendOptions(msg string, ssml bool, options []string) {
+	s.send(msg, ssml, s.System[flow.SystemChannel] == flow.ChannelVoice, options)
+}
+
+func (s *callConnector) send(msg string, ssml bool, interruptible bool, options []string) {
+	if s.System[flow.SystemChannel] == flow.ChannelTask {
+		return
+	}
+	evt := event.PromptEvent{
+		Text:          msg,
+		SSML:          ssml,
+		Voice:         *s.GetSystem(flow.SystemTextToSpeechVoice),
+		Interruptible: interruptible,
+	}
+	if s.System[flow.SystemChannel] == flow.ChannelChat {
+		if im, ok := parseInteractiveMessage(msg); ok {
+			evt.Text = im.title()
+			evt.Options = im.options()
+			msg = im.String()
+		} else if len(options) > 0 {
+			evt.Options = options
+			msg = optionList(msg, options)
+		}
+	}
+	s.emit(evt)
+	var keys <-chan rune
+	if interruptible {
+		keys = s.i
+	}
+	if s.hungUp() {
+		// Nobody is listening, so the prompt is never delivered.
+		return
+	}
+	length := promptLength(msg, ssml)
+	select {
+	case s.o <- msg:
+		if s.System[flow.SystemChannel] == flow.ChannelVoice {
+			s.advance(length)
+		}
+	case in := <-keys:
+		played := length / 2
+		if s.bargeInAfter > 0 {
+			played = s.bargeInAfter
+		}
+		if played > length {
+			played = length
+		}
+		s.advance(played)
+		s.pendingKeys = append(s.pendingKeys, in)
+		s.emit(event.PromptInterruptedEvent{Text: evt.Text, Played: played, Length: length})
+	case <-s.hangup:
+	case <-s.kill:
+	}
+}
+
+// wordsPerMinute is the speaking rate used to estimate how long a prompt takes to play.
+const wordsPerMinute = 150
+
+var ssmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// promptLength estimates how long a prompt takes to speak.
+func promptLength(msg string, ssml bool) time.Duration {
+	if ssml {
+		msg = ssmlTag.ReplaceAllString(msg, " ")
+	}
+	return time.Duration(len(strings.Fields(msg))) * time.Minute / wordsPerMinute
 }
 
 // Receive waits for a number of characters to be input.
-// If the first character is not received before the timeout time, it returns nil.
-func (s *callConnector) Receive(maxDigits int, timeout time.Duration, terminator rune) (string, bool) {
+// If the first character is not received before the timeout, it returns false.
+// Once the caller has started, entry ends when they have entered maxDigits characters or the terminator, or when they pause for longer than the interdigit timeout.
+// A T pressed after the first character is taken as a pause.
+func (s *callConnector) Receive(maxDigits int, timeout time.Duration, interdigit time.Duration, terminator string) (string, bool) {
+	if s.hungUp() {
+		return "", false
+	}
+	switch s.System[flow.SystemChannel] {
+	case flow.ChannelChat:
+		return s.receiveMessage(maxDigits, timeout, terminator)
+	case flow.ChannelTask:
+		return "", false
+	}
+	got := []rune{}
 	s.emit(event.InputEvent{
 		MaxDigits: maxDigits,
 		Timeout:   timeout,
 	})
-	got := []rune{}
-	select {
-	case <-time.After(timeout):
-		return "", false
-	case in, ok := <-s.i:
-		if !ok {
-			s.Terminate()
-			return "", true
+	if len(s.pendingKeys) > 0 {
+		// The caller started typing while the prompt was playing.
+		got, s.pendingKeys = s.pendingKeys, nil
+		if got[0] == 'T' {
+			s.advance(timeout)
+			return "", false
 		}
-		if in == 'T' {
+		for _, in := range got {
+			s.emit(event.DigitEvent{Digit: in})
+		}
+	} else {
+		select {
+		case <-time.After(timeout):
+			s.advance(timeout)
+			return "", false
+		case <-s.hangup:
 			return "", false
+		case <-s.kill:
+			return "", false
+		case <-s.t:
+			// Speech is not heard by blocks that only take key presses.
+			return "", false
+		case in, ok := <-s.i:
+			if !ok {
+				s.Terminate()
+				return "", true
+			}
+			if in == 'T' {
+				s.advance(timeout)
+				return "", false
+			}
+			got = append(got, in)
+			s.emit(event.DigitEvent{Digit: in})
 		}
-		got = append(got, in)
 	}
-	for len(got) < maxDigits && got[len(got)-1] != terminator {
-		got = append(got, <-s.i)
+	terminated := func() bool {
+		return terminator != "" && strings.HasSuffix(string(got), terminator)
 	}
-	if got[len(got)-1] == terminator {
-		if len(got) == 1 {
-			return string(got), true
+entry:
+	for len(got) < maxDigits && !terminated() {
+		select {
+		case in := <-s.i:
+			if in == 'T' {
+				s.advance(interdigit)
+				break entry
+			}
+			got = append(got, in)
+			s.emit(event.DigitEvent{Digit: in})
+		case <-time.After(interdigit):
+			s.advance(interdigit)
+			break entry
+		case <-s.t:
+			break entry
+		case <-s.hangup:
+			return "", false
+		case <-s.kill:
+			return "", false
 		}
-		got = got[:len(got)-1]
 	}
-
+	if terminated() && string(got) != terminator {
+		return strings.TrimSuffix(string(got), terminator), true
+	}
 	return string(got), true
 }
 
+// ReceiveSpeech waits for the caller to say something.
+// A key press is taken as an utterance of its own, except for T, which times out straight away. On a chat, it waits for a message.
+func (s *callConnector) ReceiveSpeech(timeout time.Duration) (string, bool) {
+	if s.hungUp() {
+		return "", false
+	}
+	switch s.System[flow.SystemChannel] {
+	case flow.ChannelChat:
+		return s.receiveMessage(0, timeout, "")
+	case flow.ChannelTask:
+		return "", false
+	}
+	s.emit(event.InputEvent{
+		Timeout: timeout,
+	})
+	select {
+	case <-time.After(timeout):
+		s.advance(timeout)
+		return "", false
+	case <-s.hangup:
+		return "", false
+	case <-s.kill:
+		return "", false
+	case utterance := <-s.t:
+		return utterance, true
+	case in := <-s.i:
+		if in == 'T' {
+			s.advance(timeout)
+			return "", false
+		}
+		return string(in), true
+	}
+}
+
 // SetExternal sets a value into the state machine.
 func (s *callConnector) SetExternal(key string, value interface{}) {
+	s.stateMutex.Lock()
 	s.External[key] = fmt.Sprintf("%v", value)
+	s.stateMutex.Unlock()
 }
 
 // SetContactData sets a value into the state machine.
@@ -197,12 +703,16 @@ func (s *callConnector) SetContactData(key string, value string) {
 		Key:   key,
 		Value: value,
 	})
+	s.stateMutex.Lock()
 	s.ContactData[key] = value
+	s.stateMutex.Unlock()
 }
 
 // SetSystem sets a value into the state machine.
 func (s *callConnector) SetSystem(key flow.SystemKey, value string) {
+	s.stateMutex.Lock()
 	s.System[key] = value
+	s.stateMutex.Unlock()
 }
 
 // GetExternal gets a value from the state machine.
@@ -232,13 +742,139 @@ func (s *callConnector) GetSystem(key flow.SystemKey) *string {
 	return &val
 }
 
+// lambdaQueue gives the working queue as it is described to lambdas, or nil if no queue has been set.
+func (s *callConnector) lambdaQueue() *lambdaPayloadQueue {
+	arn, ok := s.System[flow.SystemQueueARN]
+	if !ok {
+		return nil
+	}
+	q := lambdaPayloadQueue{
+		Name: s.System[flow.SystemQueueName],
+		ARN:  arn,
+	}
+	if n := s.System[flow.SystemQueueOutboundNumber]; n != "" {
+		q.OutboundCallerID = &lambdaPayloadContactEndpoint{
+			Type:    "TELEPHONE_NUMBER",
+			Address: n,
+		}
+	}
+	return &q
+}
+
 func (s *callConnector) IsInHours(name string, isQueue bool) (bool, error) {
-	return s.simulatorConnector.IsInHours(name, isQueue, s.Time)
+	return s.simulatorConnector.IsInHours(name, isQueue, s.now())
 }
 
 // ClearExternal allows clearing of all externalvalues in the state machine.
 func (s *callConnector) ClearExternal() {
+	s.stateMutex.Lock()
 	s.External = map[string]string{}
+	s.stateMutex.Unlock()
+}
+
+// SetLogging turns contact flow logging on or off for the rest of the call.
+func (s *callConnector) SetLogging(enabled bool) {
+	s.stateMutex.Lock()
+	s.logging = enabled
+	s.stateMutex.Unlock()
+}
+
+// log writes a contact flow log entry for a block that has just run.
+func (s *callConnector) log(m flow.Module, params map[string]interface{}, result flow.ModuleBranchCondition) error {
+	e := newFlowLogEntry(s.System[flow.SystemContactID], s.GetModuleFlowName(m.ID), m, params, result, s.now())
+	if m.Type == flow.ModuleInvokeExternalResource && result == flow.BranchSuccess {
+		e.ExternalResults = map[string]string{}
+		for k, v := range s.External {
+			e.ExternalResults[k] = v
+		}
+	}
+	return s.WriteFlowLog(e)
+}
+
+// SetRecording sets which participants of the call are recorded, and whether recordings are analysed.
+func (s *callConnector) SetRecording(agent bool, customer bool, analytics bool) {
+	s.emit(event.RecordingEvent{
+		Agent:     agent,
+		Customer:  customer,
+		Analytics: analytics,
+	})
+	s.stateMutex.Lock()
+	s.recording = &ContactRecordRecording{
+		Agent:     agent,
+		Customer:  customer,
+		Analytics: analytics,
+	}
+	s.stateMutex.Unlock()
+}
+
+// GetRoutingPriority gets the priority and age adjustment used when the call is placed in a queue.
+func (s *callConnector) GetRoutingPriority() (priority int, age time.Duration) {
+	return s.priority, s.ageAdjust
+}
+
+// SetRoutingPriority sets the priority and age adjustment used when the call is placed in a queue.
+func (s *callConnector) SetRoutingPriority(priority int, age time.Duration) {
+	s.stateMutex.Lock()
+	s.priority = priority
+	s.ageAdjust = age
+	s.stateMutex.Unlock()
+}
+
+// Enqueue places the call in a queue to wait for an agent.
+// It returns false if the queue is already holding as many contacts as it is configured to allow.
+func (s *callConnector) Enqueue(queueARN string, queueName string) bool {
+	ok := s.queues.add(QueuedContact{
+		ContactID:         s.System[flow.SystemContactID],
+		QueueName:         queueName,
+		QueueARN:          queueARN,
+		Priority:          s.priority,
+		AgeAdjustment:     s.ageAdjust,
+		EnqueuedAt:        s.now(),
+		InitiationMethod:  s.System[flow.SystemInitiationMethod],
+		PreviousContactID: s.System[flow.SystemPreviousContactID],
+		InitialContactID:  s.System[flow.SystemInitialContactID],
+		CustomerNumber:    s.System[flow.SystemCustomerNumber],
+	}, s.queueCapacity(queueARN))
+	if ok {
+		s.stateMutex.Lock()
+		s.queue = queueName
+		s.stateMutex.Unlock()
+	}
+	return ok
+}
+
+// CreateCallback queues a callback on behalf of a Create Callback block.
+// The callback is a new contact that follows on from this one. It is routed as any other queued contact, counting its wait from the end of its initial delay.
+func (s *callConnector) CreateCallback(cb module.Callback) (contactID string, err error) {
+	contactID = s.newID()
+	ok := s.queues.add(QueuedContact{
+		ContactID:         contactID,
+		QueueName:         cb.QueueName,
+		QueueARN:          cb.QueueARN,
+		Priority:          s.priority,
+		AgeAdjustment:     s.ageAdjust,
+		EnqueuedAt:        s.now().Add(cb.InitialDelay),
+		InitiationMethod:  "CALLBACK",
+		PreviousContactID: s.System[flow.SystemContactID],
+		InitialContactID:  s.System[flow.SystemInitialContactID],
+		CustomerNumber:    cb.Tel,
+	}, s.queueCapacity(cb.QueueARN))
+	if !ok {
+		return "", fmt.Errorf("queue at capacity: %s", cb.QueueName)
+	}
+	return contactID, nil
+}
+
+// NewContactID makes the ID of a new contact that follows on from this one, such as the leg of a transfer to an external number.
+func (s *callConnector) NewContactID() string {
+	return s.newID()
+}
+
+// SetEventHook records the flow to run when the given event happens later in the call.
+func (s *callConnector) SetEventHook(hook flow.EventHook, flowName string) {
+	s.stateMutex.Lock()
+	s.hooks[hook] = flowName
+	s.stateMutex.Unlock()
 }
 
 func (s *callConnector) Emit(event event.Event) {
@@ -264,14 +900,31 @@ func (s *callConnector) InvokeLambda(named string, params json.RawMessage, timeo
 				ContactID:         s.System[flow.SystemContactID],
 				PreviousContactID: s.System[flow.SystemPreviousContactID],
 				InitialContactID:  s.System[flow.SystemInitialContactID],
-				Queue:             s.GetSystem(flow.SystemQueueARN),
+				InstanceARN:       s.System[flow.SystemInstanceARN],
+				LanguageCode:      s.System[flow.SystemLanguageCode],
+				Queue:             s.lambdaQueue(),
 			},
 			Parameters: params,
 		},
 		Name: "ContactFlowEvent",
 	}
 	jsonIn, _ := json.Marshal(payloadIn)
-	out, outErr, err = s.simulatorConnector.InvokeLambda(named, string(jsonIn))
+	ctx := context.WithValue(s.ctx, lambdaRequestKey{}, LambdaRequest{
+		RequestID:   s.newID(),
+		FunctionARN: named,
+		ContactID:   s.System[flow.SystemContactID],
+	})
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	out, outErr, err = s.simulatorConnector.InvokeLambda(ctx, named, string(jsonIn))
+	// The call's own context ending is not a lambda timeout: the call is being killed.
+	timedOut := err == context.DeadlineExceeded && s.ctx.Err() == nil
+	if timedOut {
+		err = fmt.Errorf("lambda timed out after %v: %w", timeout, err)
+	}
 	s.emit(event.InvokeLambdaEvent{
 		ARN:           named,
 		Timeout:       timeout,
@@ -281,5 +934,8 @@ func (s *callConnector) InvokeLambda(named string, params json.RawMessage, timeo
 		ResponseError: outErr,
 		Error:         err,
 	})
+	if timedOut {
+		s.emit(event.LambdaTimeoutEvent{ARN: named, Timeout: timeout})
+	}
 	return
 }
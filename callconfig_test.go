@@ -0,0 +1,107 @@
+package simulator_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+func TestCallConfig(t *testing.T) {
+	sim := newTestSimulator(t, "Sample logging flow", sampleLogging, sampleChat)
+	if err := sim.SetStartingFlowFor("+441121234568", "Sample chat flow"); err != nil {
+		t.Fatalf("unexpected error setting starting flow: %v", err)
+	}
+	payloads := make(chan LambdaPayload, 1)
+	err := sim.RegisterLambda("greeting", func(ctx context.Context, in LambdaPayload) (map[string]string, error) {
+		payloads <- in
+		return map[string]string{"greeting": "hello"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		for range call.Caller.O {
+		}
+		in := <-payloads
+		data := in.Details.ContactData
+		if data.Channel != "VOICE" || data.InitiationMethod != "INBOUND" || data.LanguageCode != "en-US" {
+			t.Errorf("expected VOICE, INBOUND and en-US but got %s, %s and %s", data.Channel, data.InitiationMethod, data.LanguageCode)
+		}
+	})
+	t.Run("initial state", func(t *testing.T) {
+		arn := "arn:aws:connect:eu-west-2:456789012345:instance/eeeeeeee-eeee-4000-eeee-eeeeeeeeeeee"
+		call := startTestCall(t, sim, CallConfig{
+			Attributes:   map[string]string{"accountId": "123456"},
+			InstanceARN:  arn,
+			LanguageCode: "en-GB",
+			System: map[flow.SystemKey]string{
+				flow.SystemInitiationMethod:  "TRANSFER",
+				flow.SystemTextToSpeechVoice: "Amy",
+			},
+		})
+		for range call.Caller.O {
+		}
+		in := <-payloads
+		data := in.Details.ContactData
+		attr := map[string]string{}
+		if err := json.Unmarshal(data.Attributes, &attr); err != nil {
+			t.Fatalf("unexpected error reading attributes: %v", err)
+		}
+		if attr["accountId"] != "123456" {
+			t.Errorf("expected lambda to be given the initial attributes but got %v", attr)
+		}
+		if data.InstanceARN != arn {
+			t.Errorf("expected instance ARN of '%s' but got '%s'", arn, data.InstanceARN)
+		}
+		if data.LanguageCode != "en-GB" {
+			t.Errorf("expected language code of 'en-GB' but got '%s'", data.LanguageCode)
+		}
+		if data.InitiationMethod != "TRANSFER" {
+			t.Errorf("expected initiation method of 'TRANSFER' but got '%s'", data.InitiationMethod)
+		}
+		if v := call.System[flow.SystemTextToSpeechVoice]; v != "Amy" {
+			t.Errorf("expected voice of 'Amy' but got '%s'", v)
+		}
+		if v := call.System[flow.SystemCustomerNumber]; v != "+447878123456" {
+			t.Errorf("expected customer number not to be overridden but got '%s'", v)
+		}
+	})
+	t.Run("chat", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{
+			DestNumber:   "+441121234568",
+			Channel:      flow.ChannelChat,
+			CustomerName: "Jane",
+			Attributes:   map[string]string{"firstName": "Jane"},
+		})
+		evts := make(chan event.Event)
+		call.Subscribe(evts, Buffered(), OfType(event.MessageType))
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("Hello Jane, how can we help?")
+		expect.Prompt().WithOptions("Billing", "Sales").ToEqual("Which department?")
+		expect.Caller().ToSend("Sales")
+		expect.Prompt().ToEqual("Sales it is.")
+		call.Terminate()
+		for range call.Caller.O {
+		}
+		if msg, ok := (<-evts).(event.MessageEvent); !ok || msg.DisplayName != "Jane" {
+			t.Errorf("expected message from 'Jane' but got %v", msg)
+		}
+	})
+	t.Run("task channel", func(t *testing.T) {
+		_, err := sim.StartCall(CallConfig{
+			SourceNumber: "+447878123456",
+			DestNumber:   "+441121234567",
+			Channel:      flow.ChannelTask,
+		})
+		if err == nil {
+			t.Error("expected an error starting a call on the TASK channel but got none")
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// ChatConfig describes a chat, in the manner of the StartChatContact API.
+type ChatConfig struct {
+	// FlowName is the name of the flow that handles the chat.
+	FlowName string
+	// DisplayName is the name the customer chats under.
+	DisplayName string
+	// Attributes are contact attributes set before the flow starts.
+	Attributes map[string]string
+	// InitialMessage, if given, is the customer's first message. It is received by the first block that waits for a message.
+	InitialMessage string
+	// Time is the time the chat starts (for in-hours check).
+	Time time.Time
+}
+
+// StartChat starts a new chat asynchronously and returns a Call object for interacting with it.
+// The chat's Channel is CHAT and its InitiationMethod is API.
+// Messages from the flow are read from Caller.O, and the customer's messages are written to Caller.T.
+// Blocks that take input receive a whole message at a time, rather than a key press at a time.
+func (cs *Simulator) StartChat(config ChatConfig) (*Call, error) {
+	f, ok := cs.flows[config.FlowName]
+	if !ok {
+		return nil, fmt.Errorf("flow not found: %s. Load the flow with LoadFlow before calling this method", config.FlowName)
+	}
+	if config.DisplayName == "" {
+		return nil, errors.New("a display name must be provided in order to start a chat")
+	}
+	sc := &simulatorConnector{cs}
+	c := newCall(CallConfig{
+		Time:         config.Time,
+		Attributes:   config.Attributes,
+		Channel:      flow.ChannelChat,
+		CustomerName: config.DisplayName,
+	}, sc)
+	c.System[flow.SystemInitiationMethod] = "API"
+	if config.InitialMessage != "" {
+		c.pending = append(c.pending, config.InitialMessage)
+	}
+	c.start(sc, f.Start)
+	return c, nil
+}
+
+// receiveMessage waits for the customer's next chat message.
+// A message sent before the flow asked for one (such as the initial message) is taken without waiting.
+// A character sent on the keypad channel is taken as a message of its own, except for T, which times out straight away.
+func (s *callConnector) receiveMessage(maxDigits int, timeout time.Duration, terminator string) (string, bool) {
+	var msg string
+	if len(s.pending) > 0 {
+		msg, s.pending = s.pending[0], s.pending[1:]
+	} else {
+		s.emit(event.InputEvent{
+			MaxDigits: maxDigits,
+			Timeout:   timeout,
+		})
+		select {
+		case <-time.After(timeout):
+			s.advance(timeout)
+			return "", false
+		case <-s.hangup:
+			return "", false
+		case <-s.kill:
+			return "", false
+		case msg = <-s.t:
+		case in := <-s.i:
+			if in == 'T' {
+				s.advance(timeout)
+				return "", false
+			}
+			msg = string(in)
+		}
+	}
+	s.emit(event.MessageEvent{
+		DisplayName: s.displayName,
+		Text:        msg,
+	})
+	msg = strings.TrimSpace(msg)
+	if terminator != "" && msg != terminator {
+		msg = strings.TrimSuffix(msg, terminator)
+	}
+	return msg, true
+}
+
+// interactiveMessage is a chat message that offers the customer choices, such as a list picker or quick replies.
+type interactiveMessage struct {
+	TemplateType string `json:"templateType"`
+	Data         struct {
+		Content struct {
+			Title    string `json:"title"`
+			Elements []struct {
+				Title string `json:"title"`
+			} `json:"elements"`
+		} `json:"content"`
+	} `json:"data"`
+}
+
+// parseInteractiveMessage reads a prompt that holds an interactive message template.
+// It returns false if the prompt is ordinary text.
+func parseInteractiveMessage(msg string) (interactiveMessage, bool) {
+	im := interactiveMessage{}
+	if !strings.HasPrefix(strings.TrimSpace(msg), "{") {
+		return im, false
+	}
+	if err := json.Unmarshal([]byte(msg), &im); err != nil || im.TemplateType == "" {
+		return im, false
+	}
+	return im, true
+}
+
+func (im interactiveMessage) title() string {
+	return im.Data.Content.Title
+}
+
+func (im interactiveMessage) options() []string {
+	opts := make([]string, len(im.Data.Content.Elements))
+	for i, e := range im.Data.Content.Elements {
+		opts[i] = e.Title
+	}
+	return opts
+}
+
+// String presents the message as text: the title followed by a line for each option.
+func (im interactiveMessage) String() string {
+	return optionList(im.title(), im.options())
+}
+
+// optionList presents a chat message and the options it offers as text, with a line for each option.
+func optionList(msg string, options []string) string {
+	lines := append([]string{msg}, options...)
+	for i := 1; i < len(lines); i++ {
+		lines[i] = "- " + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+package simulator_test
+
+import (
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleChat = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000b-000000000001","type":"SendMessage","branches":[{"condition":"Success","transition":"00000000-0000-4000-000b-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-000b-000000000009"}],"parameters":[{"name":"Text","value":"Hello $.Attributes.firstName, how can we help?"}]},
+        {"id":"00000000-0000-4000-000b-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"Billing","transition":"00000000-0000-4000-000b-000000000003"},{"condition":"Evaluate","conditionType":"Equals","conditionValue":"Sales","transition":"00000000-0000-4000-000b-000000000004"},{"condition":"Timeout","transition":"00000000-0000-4000-000b-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-000b-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-000b-000000000009"}],"parameters":[{"name":"Text","value":"{\"templateType\":\"ListPicker\",\"version\":\"1.0\",\"data\":{\"content\":{\"title\":\"Which department?\",\"elements\":[{\"title\":\"Billing\"},{\"title\":\"Sales\"}]}}}"},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"60"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-000b-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000b-000000000005"}],"parameters":[{"name":"Text","value":"Billing it is.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000b-000000000004","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000b-000000000005"}],"parameters":[{"name":"Text","value":"Sales it is.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000b-000000000005","type":"Wait","branches":[{"condition":"CustomerReturned","transition":"00000000-0000-4000-000b-000000000006"},{"condition":"Timeout","transition":"00000000-0000-4000-000b-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-000b-000000000009"}],"parameters":[{"name":"Timeout","value":"300"}]},
+        {"id":"00000000-0000-4000-000b-000000000006","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000b-000000000009"}],"parameters":[{"name":"Text","value":"Welcome back.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000b-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000b-000000000001",
+    "metadata":{"name":"Sample chat flow","description":"","type":"contactFlow"}
+}`
+
+var sampleChatMenu = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0018-000000000001","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"Billing","transition":"00000000-0000-4000-0018-000000000002"},{"condition":"Evaluate","conditionType":"Equals","conditionValue":"Sales","transition":"00000000-0000-4000-0018-000000000003"},{"condition":"Timeout","transition":"00000000-0000-4000-0018-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-0018-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0018-000000000009"}],"parameters":[{"name":"Text","value":"Which department?"},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"60"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0018-000000000002","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0018-000000000009"}],"parameters":[{"name":"Text","value":"Billing it is."},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0018-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0018-000000000009"}],"parameters":[{"name":"Text","value":"Sales it is."},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0018-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0018-000000000001",
+    "metadata":{"name":"Sample chat menu flow","description":"","type":"contactFlow"}
+}`
+
+func TestChat(t *testing.T) {
+	sim := New()
+	for _, f := range []string{sampleChat, sampleChatMenu} {
+		if err := sim.LoadFlowJSON([]byte(f)); err != nil {
+			t.Fatalf("unexpected error parsing flow: %v", err)
+		}
+	}
+	config := ChatConfig{
+		FlowName:    "Sample chat flow",
+		DisplayName: "Jane",
+		Attributes:  map[string]string{"firstName": "Jane"},
+	}
+
+	t.Run("bad config", func(t *testing.T) {
+		if _, err := sim.StartChat(ChatConfig{FlowName: "Missing flow", DisplayName: "Jane"}); err == nil {
+			t.Error("expected an error starting a chat with an unknown flow but got none")
+		}
+		if _, err := sim.StartChat(ChatConfig{FlowName: "Sample chat flow"}); err == nil {
+			t.Error("expected an error starting a chat with no display name but got none")
+		}
+	})
+	t.Run("list picker", func(t *testing.T) {
+		call, err := sim.StartChat(config)
+		if err != nil {
+			t.Fatalf("unexpected error starting chat: %v", err)
+		}
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("Hello Jane, how can we help?")
+		expect.Prompt().WithOptions("Billing", "Sales").ToEqual("Which department?")
+		expect.Caller().ToSend("Billing")
+		expect.Prompt().ToEqual("Billing it is.")
+		expect.Caller().ToSend("Are you still there?")
+		expect.Prompt().ToEqual("Welcome back.")
+		if r := call.ContactRecord(); r.Channel != "CHAT" || r.InitiationMethod != "API" {
+			t.Errorf("expected channel CHAT and initiation method API but got %s and %s", r.Channel, r.InitiationMethod)
+		}
+	})
+	t.Run("options as text", func(t *testing.T) {
+		call, err := sim.StartChat(config)
+		if err != nil {
+			t.Fatalf("unexpected error starting chat: %v", err)
+		}
+		defer call.Terminate()
+		<-call.Caller.O
+		if msg, exp := <-call.Caller.O, "Which department?\n- Billing\n- Sales"; msg != exp {
+			t.Errorf("expected message of '%s' but got '%s'", exp, msg)
+		}
+	})
+	t.Run("menu options as text", func(t *testing.T) {
+		c := config
+		c.FlowName = "Sample chat menu flow"
+		call, err := sim.StartChat(c)
+		if err != nil {
+			t.Fatalf("unexpected error starting chat: %v", err)
+		}
+		expect := flowtest.New(t, call)
+		expect.Prompt().WithOptions("Billing", "Sales").ToEqual("Which department?")
+		expect.Caller().ToSend("Sales")
+		expect.Prompt().ToEqual("Sales it is.")
+
+		call, err = sim.StartChat(c)
+		if err != nil {
+			t.Fatalf("unexpected error starting chat: %v", err)
+		}
+		defer call.Terminate()
+		if msg, exp := <-call.Caller.O, "Which department?\n- Billing\n- Sales"; msg != exp {
+			t.Errorf("expected message of '%s' but got '%s'", exp, msg)
+		}
+	})
+	t.Run("initial message", func(t *testing.T) {
+		c := config
+		c.InitialMessage = "Sales"
+		call, err := sim.StartChat(c)
+		if err != nil {
+			t.Fatalf("unexpected error starting chat: %v", err)
+		}
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("Sales it is.")
+	})
+	t.Run("wait times out", func(t *testing.T) {
+		call, err := sim.StartChat(config)
+		if err != nil {
+			t.Fatalf("unexpected error starting chat: %v", err)
+		}
+		expect := flowtest.New(t, call)
+		expect.Prompt().Never().ToEqual("Welcome back.")
+		expect.Caller().ToSend("Sales")
+		expect.Caller().ToWaitForTimeout()
+	})
+}
@@ -0,0 +1,62 @@
+package simulator
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetClock sets where the simulator gets the time from.
+// It gives the start time of calls not given one in their config.
+// Time within a call is simulated: it moves on as prompts are played and as timeouts are taken, whatever the clock.
+// By default, the system clock is used.
+func (cs *Simulator) SetClock(now func() time.Time) {
+	cs.clock = now
+}
+
+// SetIDGenerator sets how the simulator makes contact IDs and lambda request IDs.
+// By default, IDs are random UUIDs. For IDs that are the same on every run, use SeededIDs.
+func (cs *Simulator) SetIDGenerator(next func() string) {
+	cs.newID = next
+}
+
+// FixedClock gives a clock that always gives the same time, for use with SetClock.
+// Calls not given a start time all start at t, and simulated time still passes within them.
+// Together with SeededIDs, it makes calls that are run one after another produce the same output on every run.
+func FixedClock(t time.Time) func() time.Time {
+	return func() time.Time {
+		return t
+	}
+}
+
+// NewDeterministic creates a call simulator that produces the same output on every run, for use in tests.
+// Calls not given a start time start at the given time, and IDs come from SeededIDs(seed).
+// Output is only the same when calls are run one after another, and encryption remains random.
+func NewDeterministic(seed int64, start time.Time) Simulator {
+	sim := New()
+	sim.SetClock(FixedClock(start))
+	sim.SetIDGenerator(SeededIDs(seed))
+	return sim
+}
+
+// SeededIDs gives an ID generator, for use with SetIDGenerator, that gives the same sequence of IDs for the same seed.
+// IDs take the form of random (version 4) UUIDs. It is safe to use from many calls at once, but then the order IDs are given out in is not fixed.
+func SeededIDs(seed int64) func() string {
+	r := rand.New(rand.NewSource(seed))
+	var m sync.Mutex
+	return func() string {
+		var id uuid.UUID
+		m.Lock()
+		r.Read(id[:])
+		m.Unlock()
+		id[6] = id[6]&0x0f | 0x40
+		id[8] = id[8]&0x3f | 0x80
+		return id.String()
+	}
+}
+
+func randomID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,76 @@
+package simulator_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+)
+
+func TestReproducibleRuns(t *testing.T) {
+	start := time.Date(2020, 9, 14, 16, 30, 0, 0, time.UTC)
+	// run makes two calls through the logging flow, one after another, and gives the flow log and all that the lambda was given.
+	run := func(t *testing.T, seed int64) string {
+		sim := NewDeterministic(seed, start)
+		loadTestFlows(t, &sim, "Sample logging flow", sampleLogging)
+		out := bytes.NewBuffer(nil)
+		sim.SetFlowLog(out)
+		err := sim.RegisterLambda("greeting", func(ctx context.Context, in LambdaPayload) (map[string]string, error) {
+			req, _ := LambdaRequestFromContext(ctx)
+			b, _ := json.Marshal(in)
+			fmt.Fprintf(out, "%s %s\n", req.RequestID, b)
+			return map[string]string{"greeting": "hello"}, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error registering lambda: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			call := startTestCall(t, &sim, CallConfig{})
+			for range call.Caller.O {
+			}
+			if !call.Time.Equal(start) {
+				t.Errorf("expected call to start at %v but it started at %v", start, call.Time)
+			}
+			if now := call.Snapshot().Time; !now.After(start) {
+				t.Errorf("expected time to pass during the call but it ended at %v", now)
+			}
+		}
+		return out.String()
+	}
+
+	first, second := run(t, 1), run(t, 1)
+	if first != second {
+		t.Errorf("expected runs with the same seed to be the same but got:\n%s\nand:\n%s", first, second)
+	}
+	ids := regexp.MustCompile(`"ContactId":"([0-9a-f-]{36})"`).FindAllStringSubmatch(first, -1)
+	if len(ids) == 0 {
+		t.Fatalf("expected output to include contact IDs but got:\n%s", first)
+	}
+	if ids[0][1] == ids[len(ids)-1][1] {
+		t.Errorf("expected each call to have its own contact ID but both had %s", ids[0][1])
+	}
+	if other := run(t, 2); other == first {
+		t.Error("expected runs with different seeds to have different IDs")
+	}
+}
+
+func TestSeededIDs(t *testing.T) {
+	next := SeededIDs(42)
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := next()
+		if !uuid.MatchString(id) {
+			t.Fatalf("expected a version 4 UUID but got '%s'", id)
+		}
+		if seen[id] {
+			t.Fatalf("expected IDs to be unique but got '%s' twice", id)
+		}
+		seen[id] = true
+	}
+}
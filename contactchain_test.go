@@ -0,0 +1,141 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+var sampleContactChain = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0017-000000000001","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0017-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0017-000000000009"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:contact-lookup"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0017-000000000002","type":"SetQueue","branches":[{"condition":"Success","transition":"00000000-0000-4000-0017-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0017-000000000009"}],"parameters":[{"name":"Queue","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001","namespace":null,"resourceName":"Sales"}]},
+        {"id":"00000000-0000-4000-0017-000000000003","type":"SetCallBackNumber","branches":[{"condition":"Success","transition":"00000000-0000-4000-0017-000000000004"},{"condition":"InvalidPhoneNumber","transition":"00000000-0000-4000-0017-000000000009"},{"condition":"NonDialableNumber","transition":"00000000-0000-4000-0017-000000000009"}],"parameters":[{"name":"CallBackNumber","value":"+447878654321"}]},
+        {"id":"00000000-0000-4000-0017-000000000004","type":"CreateCallback","branches":[{"condition":"Success","transition":"00000000-0000-4000-0017-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-0017-000000000009"}],"parameters":[{"name":"InitialDelaySeconds","value":5},{"name":"RetryDelaySeconds","value":600},{"name":"MaxRetryAttempts","value":1}]},
+        {"id":"00000000-0000-4000-0017-000000000005","type":"Transfer","branches":[{"condition":"Success","transition":"00000000-0000-4000-0017-000000000006"},{"condition":"CallFailure","transition":"00000000-0000-4000-0017-000000000009"},{"condition":"Timeout","transition":"00000000-0000-4000-0017-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0017-000000000009"}],"parameters":[{"name":"TimeLimit","value":"30"},{"name":"BlindTransfer","value":false},{"name":"PhoneNumber","value":"+441234567890"}],"target":"PhoneNumber"},
+        {"id":"00000000-0000-4000-0017-000000000006","type":"Transfer","branches":[{"condition":"AtCapacity","transition":"00000000-0000-4000-0017-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0017-000000000009"}],"parameters":[],"target":"Queue"},
+        {"id":"00000000-0000-4000-0017-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0017-000000000001",
+    "metadata":{"name":"Sample contact chain flow","description":"","type":"contactFlow"}
+}`
+
+var sampleAgentTransfer = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0017-000000000011","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0017-000000000012"},{"condition":"Error","transition":"00000000-0000-4000-0017-000000000012"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:contact-lookup"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0017-000000000012","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0017-000000000011",
+    "metadata":{"name":"Sample agent transfer flow","description":"","type":"queueTransfer"}
+}`
+
+func TestContactChain(t *testing.T) {
+	sim := newTestSimulator(t, "Sample contact chain flow", sampleContactChain, sampleAgentTransfer)
+	payloads := make(chan LambdaPayload, 1)
+	err := sim.RegisterLambda("contact-lookup", func(ctx context.Context, in LambdaPayload) (map[string]string, error) {
+		payloads <- in
+		return map[string]string{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+	call := startTestCall(t, sim, CallConfig{
+		Attributes: map[string]string{"accountId": "123456"},
+	})
+	evts := make(chan event.Event)
+	call.Subscribe(evts, Buffered(), OfType(event.ContactCreatedType))
+	if _, err := call.AgentTransfer("Sample agent transfer flow", nil); err == nil {
+		t.Error("expected an error transferring a call that is still in a flow")
+	}
+	for range call.Caller.O {
+	}
+	<-payloads
+	contactID := call.System[flow.SystemContactID]
+
+	legs := []event.ContactCreatedEvent{}
+	for evt := range evts {
+		legs = append(legs, evt.(event.ContactCreatedEvent))
+	}
+	if len(legs) != 2 {
+		t.Fatalf("expected a callback and an external transfer to create 2 contacts but got %d", len(legs))
+	}
+	for i, method := range []string{"CALLBACK", "TRANSFER"} {
+		leg := legs[i]
+		if leg.InitiationMethod != method {
+			t.Errorf("expected contact %d to be a %s but got %s", i, method, leg.InitiationMethod)
+		}
+		if leg.ContactID == "" || leg.ContactID == contactID {
+			t.Errorf("expected contact %d to have a new contact ID but got '%s'", i, leg.ContactID)
+		}
+		if leg.PreviousContactID != contactID || leg.InitialContactID != contactID {
+			t.Errorf("expected contact %d to follow on from %s but got previous %s and initial %s", i, contactID, leg.PreviousContactID, leg.InitialContactID)
+		}
+	}
+	if id := call.System[flow.SystemContactID]; id != contactID {
+		t.Errorf("expected the call to keep its contact ID after transferring to a number but got '%s'", id)
+	}
+
+	var callback *QueuedContact
+	for _, q := range sim.QueuedContacts("Sales") {
+		if q.InitiationMethod == "CALLBACK" {
+			q := q
+			callback = &q
+		}
+	}
+	if callback == nil {
+		t.Fatal("expected a callback to be queued")
+	}
+	if callback.ContactID != legs[0].ContactID || callback.PreviousContactID != contactID || callback.CustomerNumber != "+447878654321" {
+		t.Errorf("expected callback %s from %s to +447878654321 but got %+v", legs[0].ContactID, contactID, *callback)
+	}
+
+	// transfer has an agent transfer the call, and checks the new contact follows on from it.
+	transfer := func(t *testing.T, from *Call) *Call {
+		var evts chan event.Event
+		to, err := from.AgentTransfer("Sample agent transfer flow", func(to *Call) {
+			evts = make(chan event.Event)
+			to.Subscribe(evts, Buffered(), OfType(event.ContactCreatedType))
+		})
+		if err != nil {
+			t.Fatalf("unexpected error transferring call: %v", err)
+		}
+		for range to.Caller.O {
+		}
+		data := (<-payloads).Details.ContactData
+		if data.ContactID != to.System[flow.SystemContactID] || data.ContactID == from.System[flow.SystemContactID] {
+			t.Errorf("expected lambda to be given the new contact ID but got '%s'", data.ContactID)
+		}
+		if data.PreviousContactID != from.System[flow.SystemContactID] {
+			t.Errorf("expected previous contact ID of '%s' but got '%s'", from.System[flow.SystemContactID], data.PreviousContactID)
+		}
+		if data.InitialContactID != contactID {
+			t.Errorf("expected initial contact ID of '%s' but got '%s'", contactID, data.InitialContactID)
+		}
+		if data.InitiationMethod != "TRANSFER" {
+			t.Errorf("expected initiation method of 'TRANSFER' but got '%s'", data.InitiationMethod)
+		}
+		if to.ContactData["accountId"] != "123456" {
+			t.Error("expected the transfer to keep the call's attributes")
+		}
+		exp := event.ContactCreatedEvent{
+			ContactID:         data.ContactID,
+			PreviousContactID: data.PreviousContactID,
+			InitialContactID:  contactID,
+			InitiationMethod:  "TRANSFER",
+			Channel:           "VOICE",
+		}
+		if evt := <-evts; evt != exp {
+			t.Errorf("expected %v but got %v", exp, evt)
+		}
+		return to
+	}
+	first := transfer(t, call)
+	transfer(t, first)
+
+	if _, err := first.AgentTransfer("Missing flow", nil); err == nil {
+		t.Error("expected an error transferring to an unknown flow")
+	}
+}
@@ -0,0 +1,97 @@
+package simulator
+
+import (
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// ContactRecord summarises a call in the manner of the contact trace record (CTR) produced by Amazon Connect.
+type ContactRecord struct {
+	ContactID           string                  `json:"ContactId"`
+	InitialContactID    string                  `json:"InitialContactId"`
+	PreviousContactID   string                  `json:"PreviousContactId"`
+	Channel             string                  `json:"Channel"`
+	InitiationMethod    string                  `json:"InitiationMethod"`
+	InitiationTimestamp time.Time               `json:"InitiationTimestamp"`
+	CustomerEndpoint    string                  `json:"CustomerEndpoint"`
+	SystemEndpoint      string                  `json:"SystemEndpoint"`
+	Attributes          map[string]string       `json:"Attributes"`
+	Queue               *ContactRecordQueue     `json:"Queue"`
+	Recording           *ContactRecordRecording `json:"Recording"`
+	DisconnectReason    event.DisconnectReason  `json:"DisconnectReason"`
+	// AnsweringMachineDetectionStatus is set for outbound calls.
+	AnsweringMachineDetectionStatus string `json:"AnsweringMachineDetectionStatus,omitempty"`
+	// Name, Description, References and ScheduledTimestamp are set for tasks.
+	Name               string                   `json:"Name,omitempty"`
+	Description        string                   `json:"Description,omitempty"`
+	References         []ContactRecordReference `json:"References,omitempty"`
+	ScheduledTimestamp *time.Time               `json:"ScheduledTimestamp,omitempty"`
+	// RelatedContactID is the contact that created this one, such as the call that created a task.
+	RelatedContactID string `json:"RelatedContactId,omitempty"`
+}
+
+// ContactRecordReference is a link attached to a task.
+type ContactRecordReference struct {
+	Name  string `json:"Name"`
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// ContactRecordQueue is the queue the contact was last placed in.
+type ContactRecordQueue struct {
+	Name string `json:"Name"`
+	ARN  string `json:"ARN"`
+}
+
+// ContactRecordRecording is the recording behavior last set by a Set Recording Behavior block.
+type ContactRecordRecording struct {
+	Agent     bool `json:"Agent"`
+	Customer  bool `json:"Customer"`
+	Analytics bool `json:"Analytics"`
+}
+
+// ContactRecord builds a record of the call so far.
+// DisconnectReason is empty until the call has ended. It is safe to use while the call is running.
+func (c *Call) ContactRecord() ContactRecord {
+	c.stateMutex.RLock()
+	defer c.stateMutex.RUnlock()
+	r := ContactRecord{
+		ContactID:                       c.System[flow.SystemContactID],
+		InitialContactID:                c.System[flow.SystemInitialContactID],
+		PreviousContactID:               c.System[flow.SystemPreviousContactID],
+		Channel:                         c.System[flow.SystemChannel],
+		InitiationMethod:                c.System[flow.SystemInitiationMethod],
+		InitiationTimestamp:             c.Time,
+		CustomerEndpoint:                c.System[flow.SystemCustomerNumber],
+		SystemEndpoint:                  c.System[flow.SystemDialedNumber],
+		Attributes:                      map[string]string{},
+		DisconnectReason:                c.disconnectReason,
+		AnsweringMachineDetectionStatus: c.System[flow.SystemAnsweringMachineStatus],
+	}
+	for k, v := range c.ContactData {
+		r.Attributes[k] = v
+	}
+	if arn, ok := c.System[flow.SystemQueueARN]; ok {
+		r.Queue = &ContactRecordQueue{
+			Name: c.System[flow.SystemQueueName],
+			ARN:  arn,
+		}
+	}
+	if c.task != nil {
+		r.Name = c.System[flow.SystemTaskName]
+		r.Description = c.System[flow.SystemTaskDescription]
+		r.References = c.task.contactRecordReferences()
+		r.RelatedContactID = c.task.relatedContactID
+		if !c.task.scheduled.IsZero() {
+			scheduled := c.task.scheduled
+			r.ScheduledTimestamp = &scheduled
+		}
+	}
+	if c.recording != nil {
+		rec := *c.recording
+		r.Recording = &rec
+	}
+	return r
+}
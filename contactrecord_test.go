@@ -0,0 +1,71 @@
+package simulator_test
+
+import (
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleRecordingBehavior = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0004-000000000001","type":"SetRecordingBehavior","branches":[{"condition":"Success","transition":"00000000-0000-4000-0004-000000000002"}],"parameters":[{"name":"RecordingBehaviorOption","value":"Enable"},{"name":"RecordingParticipantOption","value":"Both"},{"name":"AnalyticsBehaviorOption","value":"Enable"}]},
+        {"id":"00000000-0000-4000-0004-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0004-000000000003"},{"condition":"Timeout","transition":"00000000-0000-4000-0004-000000000006"},{"condition":"NoMatch","transition":"00000000-0000-4000-0004-000000000006"},{"condition":"Error","transition":"00000000-0000-4000-0004-000000000006"}],"parameters":[{"name":"Text","value":"Press 1 to make a payment."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}]},
+        {"id":"00000000-0000-4000-0004-000000000003","type":"SetRecordingBehavior","branches":[{"condition":"Success","transition":"00000000-0000-4000-0004-000000000004"}],"parameters":[{"name":"RecordingBehaviorOption","value":"Disable"},{"name":"RecordingParticipantOption","value":"Both"}]},
+        {"id":"00000000-0000-4000-0004-000000000004","type":"StoreUserInput","branches":[{"condition":"Success","transition":"00000000-0000-4000-0004-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-0004-000000000006"}],"parameters":[{"name":"Text","value":"Please enter your card number."},{"name":"TextToSpeechType","value":"text"},{"name":"CustomerInputType","value":"Custom"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":20},{"name":"EncryptEntry","value":false}]},
+        {"id":"00000000-0000-4000-0004-000000000005","type":"SetRecordingBehavior","branches":[{"condition":"Success","transition":"00000000-0000-4000-0004-000000000006"}],"parameters":[{"name":"RecordingBehaviorOption","value":"Enable"},{"name":"RecordingParticipantOption","value":"Both"}]},
+        {"id":"00000000-0000-4000-0004-000000000006","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0004-000000000001",
+    "metadata":{"name":"Sample recording behavior flow","description":"","type":"contactFlow"}
+}`
+
+// sampleRecordedEntry forgets to stop recording before taking a card number.
+var sampleRecordedEntry = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0019-000000000001","type":"SetRecordingBehavior","branches":[{"condition":"Success","transition":"00000000-0000-4000-0019-000000000002"}],"parameters":[{"name":"RecordingBehaviorOption","value":"Enable"},{"name":"RecordingParticipantOption","value":"Both"}]},
+        {"id":"00000000-0000-4000-0019-000000000002","type":"StoreUserInput","branches":[{"condition":"Success","transition":"00000000-0000-4000-0019-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0019-000000000003"}],"parameters":[{"name":"Text","value":"Please enter your card number, followed by the hash key."},{"name":"TextToSpeechType","value":"text"},{"name":"CustomerInputType","value":"Custom"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":20},{"name":"EncryptEntry","value":false}]},
+        {"id":"00000000-0000-4000-0019-000000000003","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0019-000000000001",
+    "metadata":{"name":"Sample recorded entry flow","description":"","type":"contactFlow"}
+}`
+
+func TestRecordingBehavior(t *testing.T) {
+	sim := newTestSimulator(t, "Sample recording behavior flow", sampleRecordingBehavior)
+	call := startTestCall(t, sim, CallConfig{})
+	expect := flowtest.New(t, call)
+	expect.Recording().Never().ToBeOnDuringStoredInput()
+
+	expect.Recording().WithAgent().WithCustomer().WithAnalytics().ToStart()
+	expect.Prompt().ToContain("Press 1")
+	expect.Caller().ToPress('1')
+	expect.Recording().ToStop()
+	expect.Prompt().ToContain("card number")
+	expect.Caller().ToEnter("4111111111111111#")
+	expect.Recording().Not().WithAnalytics().ToStart()
+
+	r := call.ContactRecord()
+	if r.Recording == nil {
+		t.Fatal("expected contact record to include recording behavior but it was nil")
+	}
+	if exp := (ContactRecordRecording{Agent: true, Customer: true}); *r.Recording != exp {
+		t.Errorf("expected contact record recording of %+v but got %+v", exp, *r.Recording)
+	}
+	if r.DisconnectReason != event.DisconnectContactFlow {
+		t.Errorf("expected contact record disconnect reason of %s but got %s", event.DisconnectContactFlow, r.DisconnectReason)
+	}
+	if r.CustomerEndpoint != "+447878123456" {
+		t.Errorf("expected contact record customer endpoint of +447878123456 but got %s", r.CustomerEndpoint)
+	}
+}
+
+func TestRecordingDuringInterruptedEntry(t *testing.T) {
+	sim := newTestSimulator(t, "Sample recorded entry flow", sampleRecordedEntry)
+	call := startTestCall(t, sim, CallConfig{})
+	expect := flowtest.New(t, call)
+	expect.Caller().ToInterrupt("4111#")
+	// The card number is recorded even though the caller cut the prompt short.
+	expect.Recording().ToBeOnDuringStoredInput()
+}
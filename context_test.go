@@ -0,0 +1,131 @@
+package simulator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+var sampleContext = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0012-000000000001","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0012-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0012-000000000009"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:greeting"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0012-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0012-000000000009"},{"condition":"Timeout","transition":"00000000-0000-4000-0012-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-0012-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0012-000000000009"}],"parameters":[{"name":"Text","value":"$.External.greeting Press 1 to continue."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0012-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0012-000000000001",
+    "metadata":{"name":"Sample context flow","description":"","type":"contactFlow"}
+}`
+
+func TestStartCallContext(t *testing.T) {
+	type greeting struct {
+		Greeting string `json:"greeting"`
+	}
+	var deadline time.Time
+	var hasDeadline bool
+	var req LambdaRequest
+	var hasReq bool
+	sim := newTestSimulator(t, "Sample context flow", sampleContext)
+	sim.RegisterLambda("greeting", func(ctx context.Context, in LambdaPayload) (greeting, error) {
+		deadline, hasDeadline = ctx.Deadline()
+		req, hasReq = LambdaRequestFromContext(ctx)
+		return greeting{"Hello."}, nil
+	})
+	config := CallConfig{
+		SourceNumber: testCustomerNumber,
+		DestNumber:   testDialledNumber,
+	}
+
+	t.Run("lambda context", func(t *testing.T) {
+		invoked := time.Now()
+		call, err := sim.StartCallContext(context.Background(), config)
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		<-call.Caller.O
+		call.Terminate()
+		for range call.Caller.O {
+		}
+		if !hasDeadline {
+			t.Fatal("expected lambda context to have a deadline")
+		}
+		if d := deadline.Sub(invoked); d < 3*time.Second || d > 4*time.Second {
+			t.Errorf("expected deadline about 3 seconds after invocation but it was %v", d)
+		}
+		if !hasReq {
+			t.Fatal("expected lambda context to carry the request")
+		}
+		if !strings.HasSuffix(req.FunctionARN, "function:greeting") {
+			t.Errorf("expected function ARN of greeting lambda but got '%s'", req.FunctionARN)
+		}
+		if req.RequestID == "" {
+			t.Error("expected request ID to be set")
+		}
+		if req.ContactID != call.ContactRecord().ContactID {
+			t.Errorf("expected contact ID of '%s' but got '%s'", call.ContactRecord().ContactID, req.ContactID)
+		}
+		if call.Err != nil {
+			t.Errorf("expected terminated call to have no error but got %v", call.Err)
+		}
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		call, err := sim.StartCallContext(ctx, config)
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		evts := make(chan event.Event, 64)
+		call.Subscribe(evts)
+		if p := <-call.Caller.O; p != "Hello. Press 1 to continue." {
+			t.Fatalf("expected greeting prompt but got '%s'", p)
+		}
+		cancel()
+		for range call.Caller.O {
+		}
+		if call.Err != context.Canceled {
+			t.Errorf("expected call error of %v but got %v", context.Canceled, call.Err)
+		}
+		var reason event.DisconnectReason
+		for evt := range evts {
+			if d, ok := evt.(event.DisconnectEvent); ok {
+				reason = d.Reason
+			}
+		}
+		if reason != event.DisconnectTelecom {
+			t.Errorf("expected disconnect reason of %s but got %s", event.DisconnectTelecom, reason)
+		}
+	})
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		call, err := sim.StartCallContext(ctx, config)
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		<-call.Caller.O
+		for range call.Caller.O {
+		}
+		if call.Err != context.DeadlineExceeded {
+			t.Errorf("expected call error of %v but got %v", context.DeadlineExceeded, call.Err)
+		}
+	})
+	t.Run("cancel after end", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		call, err := sim.StartCallContext(ctx, config)
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		<-call.Caller.O
+		call.Caller.I <- '1'
+		for range call.Caller.O {
+		}
+		cancel()
+		call.Terminate()
+		if call.Err != nil {
+			t.Errorf("expected call to end without error but got %v", call.Err)
+		}
+	})
+}
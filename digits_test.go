@@ -0,0 +1,79 @@
+package simulator_test
+
+import (
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleDigits = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000f-000000000001","type":"StoreUserInput","branches":[{"condition":"Success","transition":"00000000-0000-4000-000f-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-000f-000000000009"}],"parameters":[{"name":"Text","value":"Please enter your account number, followed by star star."},{"name":"TextToSpeechType","value":"text"},{"name":"CustomerInputType","value":"Custom"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":8},{"name":"TerminatorDigits","value":"**"},{"name":"InterdigitTimeout","value":"2"},{"name":"EncryptEntry","value":false},{"name":"DisableCancel","value":false}]},
+        {"id":"00000000-0000-4000-000f-000000000002","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-000f-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-000f-000000000009"}],"parameters":[{"name":"Attribute","value":"Stored customer input","key":"account","namespace":"System"}]},
+        {"id":"00000000-0000-4000-000f-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000f-000000000009"}],"parameters":[{"name":"Text","value":"You entered $.Attributes.account.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000f-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000f-000000000001",
+    "metadata":{"name":"Sample digits flow","description":"","type":"contactFlow"}
+}`
+
+func TestDigitCollection(t *testing.T) {
+	sim := newTestSimulator(t, "Sample digits flow", sampleDigits)
+	prompt := "Please enter your account number, followed by star star."
+
+	t.Run("max digits", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual(prompt)
+		expect.Caller().ToEnter("12345678")
+		expect.Prompt().ToEqual("You entered 12345678.")
+	})
+	t.Run("terminator", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual(prompt)
+		expect.Caller().ToEnter("12*3**")
+		expect.Prompt().ToEqual("You entered 12*3.")
+	})
+	t.Run("pause after partial entry", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual(prompt)
+		expect.Caller().ToStartEntering("123")
+		expect.Caller().ToWaitForTimeout()
+		expect.Prompt().ToEqual("You entered 123.")
+	})
+	t.Run("finish partial entry", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual(prompt)
+		expect.Caller().ToStartEntering("123")
+		expect.Caller().ToEnter("45**")
+		expect.Prompt().ToEqual("You entered 12345.")
+	})
+	t.Run("digit events", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		evts := make(chan event.Event, 64)
+		call.Subscribe(evts)
+		if p := <-call.Caller.O; p != prompt {
+			t.Fatalf("expected account prompt but got '%s'", p)
+		}
+		call.Caller.I <- '4'
+		call.Caller.I <- '2'
+		call.Caller.I <- 'T'
+		if p := <-call.Caller.O; p != "You entered 42." {
+			t.Errorf("expected entry to be taken after a pause but got '%s'", p)
+		}
+		digits := ""
+		for evt := range evts {
+			if d, ok := evt.(event.DigitEvent); ok {
+				digits += string(d.Digit)
+			}
+		}
+		if digits != "42" {
+			t.Errorf("expected digit events for '42' but got '%s'", digits)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Details of the AWS Encryption SDK message format, as used by Amazon Connect to encrypt customer input.
+// Messages use version 1 of the format, with the algorithm suite AES-256-GCM, HKDF-SHA384 and ECDSA-P384 signing (0x0378).
+// See https://docs.aws.amazon.com/encryption-sdk/latest/developer-guide/message-format.html
+const (
+	// connectKeyProvider is the provider ID Amazon Connect gives its raw RSA master keys.
+	connectKeyProvider = "AmazonConnect"
+	// publicKeyContextKey is the encryption context key holding the key used to verify the message signature.
+	publicKeyContextKey = "aws-crypto-public-key"
+	messageVersion      = 0x01
+	messageType         = 0x80
+	algorithmID         = 0x0378
+	contentTypeFramed   = 0x02
+	dataKeyLength       = 32
+	ivLength            = 12
+	frameLength         = 4096
+	finalFrameMarker    = 0xFFFFFFFF
+	frameAAD            = "AWSKMSEncryptionClient Frame"
+	finalFrameAAD       = "AWSKMSEncryptionClient Final Frame"
+)
+
+// SetCertificateEncryption makes Store Customer Input blocks encrypt entries as Amazon Connect does (see EncryptWithCertificate).
+// The result can be decrypted by the AWS Encryption SDK with the private key matching the certificate in the block.
+func (cs *Simulator) SetCertificateEncryption() {
+	cs.encrypt = EncryptWithCertificate
+}
+
+// EncryptWithCertificate encrypts in using the public key in a PEM encoded X.509 certificate.
+// The output is a message in the AWS Encryption SDK format, with the data key wrapped by RSA-OAEP (SHA-512) under the given key ID.
+// It errors if the certificate cannot be read or does not hold an RSA key.
+func EncryptWithCertificate(in string, keyID string, cert []byte) ([]byte, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return nil, errors.New("encryption key is not a PEM encoded certificate")
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse encryption certificate: %v", err)
+	}
+	pub, ok := c.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("encryption certificate does not hold an RSA key")
+	}
+
+	messageID := make([]byte, 16)
+	dataKey := make([]byte, dataKeyLength)
+	if _, err = rand.Read(messageID); err != nil {
+		return nil, err
+	}
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	signingKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha512.New(), rand.Reader, pub, dataKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	key, err := gcmFor(deriveKey(dataKey, messageID))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &bytes.Buffer{}
+	msg.WriteByte(messageVersion)
+	msg.WriteByte(messageType)
+	writeUint16(msg, algorithmID)
+	msg.Write(messageID)
+	context := serializeContext(map[string]string{
+		publicKeyContextKey: base64.StdEncoding.EncodeToString(compressPoint(&signingKey.PublicKey)),
+	})
+	writeUint16(msg, len(context))
+	msg.Write(context)
+	writeUint16(msg, 1)
+	writeField(msg, []byte(connectKeyProvider))
+	writeField(msg, []byte(keyID))
+	writeField(msg, encryptedKey)
+	msg.WriteByte(contentTypeFramed)
+	msg.Write([]byte{0, 0, 0, 0})
+	msg.WriteByte(ivLength)
+	writeUint32(msg, uint32(frameLength))
+
+	// The header is authenticated with an all zero IV, as the key is unique to this message.
+	headerIV := make([]byte, ivLength)
+	headerTag := key.Seal(nil, headerIV, nil, msg.Bytes())
+	msg.Write(headerIV)
+	msg.Write(headerTag)
+
+	plaintext := []byte(in)
+	for seq := uint32(1); ; seq++ {
+		final := len(plaintext) < frameLength
+		n := frameLength
+		if final {
+			n = len(plaintext)
+		}
+		content := plaintext[:n]
+		plaintext = plaintext[n:]
+		iv := frameIV(seq)
+		aad := frameAAD
+		if final {
+			aad = finalFrameAAD
+			writeUint32(msg, finalFrameMarker)
+		}
+		writeUint32(msg, seq)
+		msg.Write(iv)
+		if final {
+			writeUint32(msg, uint32(len(content)))
+		}
+		msg.Write(key.Seal(nil, iv, content, bodyAAD(messageID, aad, seq, len(content))))
+		if final {
+			break
+		}
+	}
+
+	digest := sha512.Sum384(msg.Bytes())
+	r, s, err := ecdsa.Sign(rand.Reader, signingKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		return nil, err
+	}
+	writeField(msg, signature)
+	return msg.Bytes(), nil
+}
+
+// deriveKey derives the message's encryption key from its data key with HKDF-SHA384 (with no salt).
+func deriveKey(dataKey []byte, messageID []byte) []byte {
+	extract := hmac.New(sha512.New384, make([]byte, sha512.Size384))
+	extract.Write(dataKey)
+	expand := hmac.New(sha512.New384, extract.Sum(nil))
+	info := make([]byte, 2, 2+len(messageID))
+	binary.BigEndian.PutUint16(info, algorithmID)
+	expand.Write(append(info, messageID...))
+	expand.Write([]byte{1})
+	return expand.Sum(nil)[:dataKeyLength]
+}
+
+func gcmFor(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// frameIV gives the IV of a frame of the message body, which is its sequence number padded with zeros.
+func frameIV(seq uint32) []byte {
+	iv := make([]byte, ivLength)
+	binary.BigEndian.PutUint32(iv[ivLength-4:], seq)
+	return iv
+}
+
+// bodyAAD gives the additional authenticated data of a frame of the message body.
+func bodyAAD(messageID []byte, content string, seq uint32, length int) []byte {
+	aad := &bytes.Buffer{}
+	aad.Write(messageID)
+	aad.WriteString(content)
+	writeUint32(aad, seq)
+	binary.Write(aad, binary.BigEndian, uint64(length))
+	return aad.Bytes()
+}
+
+// serializeContext writes an encryption context as key-value pairs, sorted by key.
+func serializeContext(context map[string]string) []byte {
+	keys := make([]string, 0, len(context))
+	for k := range context {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b := &bytes.Buffer{}
+	writeUint16(b, len(keys))
+	for _, k := range keys {
+		writeField(b, []byte(k))
+		writeField(b, []byte(context[k]))
+	}
+	return b.Bytes()
+}
+
+// compressPoint writes an elliptic curve public key in compressed form (SEC 1, section 2.3.3).
+func compressPoint(pub *ecdsa.PublicKey) []byte {
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	b := make([]byte, 1+size)
+	b[0] = byte(2 + pub.Y.Bit(0))
+	x := pub.X.Bytes()
+	copy(b[1+size-len(x):], x)
+	return b
+}
+
+func writeUint16(b *bytes.Buffer, n int) {
+	binary.Write(b, binary.BigEndian, uint16(n))
+}
+
+func writeUint32(b *bytes.Buffer, n uint32) {
+	binary.Write(b, binary.BigEndian, n)
+}
+
+// writeField writes a length-prefixed field.
+func writeField(b *bytes.Buffer, field []byte) {
+	writeUint16(b, len(field))
+	b.Write(field)
+}
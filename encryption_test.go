@@ -0,0 +1,201 @@
+package simulator_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/module"
+)
+
+var sampleEncryption = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0010-000000000001","type":"StoreUserInput","branches":[{"condition":"Success","transition":"00000000-0000-4000-0010-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0010-000000000009"}],"parameters":[{"name":"Text","value":"Please enter your card number, followed by the hash key."},{"name":"TextToSpeechType","value":"text"},{"name":"CustomerInputType","value":"Custom"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":20},{"name":"EncryptEntry","value":true},{"name":"EncryptionKeyId","value":"test-key-1","namespace":null},{"name":"EncryptionKey","value":CERTIFICATE,"namespace":null}]},
+        {"id":"00000000-0000-4000-0010-000000000002","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0010-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0010-000000000009"}],"parameters":[{"name":"Attribute","value":"Stored customer input","key":"card","namespace":"System"}]},
+        {"id":"00000000-0000-4000-0010-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0010-000000000001",
+    "metadata":{"name":"Sample encryption flow","description":"","type":"contactFlow"}
+}`
+
+func TestCertificateEncryption(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "simulator test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("unexpected error creating certificate: %v", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	start := func(t *testing.T, cert string) *Call {
+		certJSON, _ := json.Marshal(cert)
+		sim := newTestSimulator(t, "Sample encryption flow", strings.Replace(sampleEncryption, "CERTIFICATE", string(certJSON), 1))
+		sim.SetCertificateEncryption()
+		call := startTestCall(t, sim, CallConfig{})
+		<-call.Caller.O
+		for _, r := range "4111111111111111#" {
+			call.Caller.I <- r
+		}
+		for range call.Caller.O {
+		}
+		return call
+	}
+
+	t.Run("decrypts with private key", func(t *testing.T) {
+		call := start(t, string(cert))
+		if call.Err != nil {
+			t.Fatalf("unexpected error in call: %v", call.Err)
+		}
+		msg, err := base64.StdEncoding.DecodeString(call.ContactData["card"])
+		if err != nil {
+			t.Fatalf("expected stored input to be base64 but got error: %v", err)
+		}
+		keyID, plaintext, err := decryptMessage(msg, priv)
+		if err != nil {
+			t.Fatalf("unexpected error decrypting: %v", err)
+		}
+		if keyID != "test-key-1" {
+			t.Errorf("expected key ID of 'test-key-1' but got '%s'", keyID)
+		}
+		if plaintext != "4111111111111111" {
+			t.Errorf("expected decrypted input of '4111111111111111' but got '%s'", plaintext)
+		}
+	})
+	t.Run("different each time", func(t *testing.T) {
+		a, _ := EncryptWithCertificate("1234", "test-key-1", cert)
+		b, _ := EncryptWithCertificate("1234", "test-key-1", cert)
+		if bytes.Equal(a, b) {
+			t.Error("expected encrypting the same input twice to give different messages")
+		}
+	})
+	t.Run("bad certificate", func(t *testing.T) {
+		call := start(t, "Certificate to use for encryption should be provided here.")
+		var blockErr *module.Error
+		if !errors.As(call.Err, &blockErr) || blockErr.Err.Error() != "encryption key is not a PEM encoded certificate" {
+			t.Fatalf("expected call to end with certificate error but got %v", call.Err)
+		}
+		if blockErr.ModuleID != "00000000-0000-4000-0010-000000000001" {
+			t.Errorf("expected error from the Store Customer Input block but got %s", blockErr.ModuleID)
+		}
+	})
+}
+
+// decryptMessage reads an AWS Encryption SDK message with a single framed body, checking every authentication tag and the signature.
+func decryptMessage(msg []byte, priv *rsa.PrivateKey) (keyID string, plaintext string, err error) {
+	r := bytes.NewReader(msg)
+	u8 := func() (v uint8) { binary.Read(r, binary.BigEndian, &v); return }
+	u16 := func() (v uint16) { binary.Read(r, binary.BigEndian, &v); return }
+	u32 := func() (v uint32) { binary.Read(r, binary.BigEndian, &v); return }
+	next := func(n int) []byte { b := make([]byte, n); r.Read(b); return b }
+	field := func() []byte { return next(int(u16())) }
+
+	if v, typ, alg := u8(), u8(), u16(); v != 1 || typ != 0x80 || alg != 0x0378 {
+		return "", "", fmt.Errorf("unexpected version %d, type %x or algorithm %x", v, typ, alg)
+	}
+	messageID := next(16)
+	context := bytes.NewReader(field())
+	var pairs uint16
+	binary.Read(context, binary.BigEndian, &pairs)
+	var publicKey []byte
+	for i := 0; i < int(pairs); i++ {
+		var kl, vl uint16
+		binary.Read(context, binary.BigEndian, &kl)
+		k := make([]byte, kl)
+		context.Read(k)
+		binary.Read(context, binary.BigEndian, &vl)
+		v := make([]byte, vl)
+		context.Read(v)
+		if string(k) == "aws-crypto-public-key" {
+			publicKey, _ = base64.StdEncoding.DecodeString(string(v))
+		}
+	}
+	if u16() != 1 {
+		return "", "", errors.New("expected one encrypted data key")
+	}
+	if provider := string(field()); provider != "AmazonConnect" {
+		return "", "", fmt.Errorf("unexpected key provider %s", provider)
+	}
+	keyID = string(field())
+	dataKey, err := rsa.DecryptOAEP(sha512.New(), rand.Reader, priv, field(), nil)
+	if err != nil {
+		return "", "", err
+	}
+	if ct, reserved, ivLen, frameLen := u8(), u32(), u8(), u32(); ct != 2 || reserved != 0 || ivLen != 12 || frameLen != 4096 {
+		return "", "", errors.New("unexpected content type, IV length or frame length")
+	}
+	header := msg[:len(msg)-r.Len()]
+
+	prk := hmac.New(sha512.New384, make([]byte, 48))
+	prk.Write(dataKey)
+	okm := hmac.New(sha512.New384, prk.Sum(nil))
+	okm.Write([]byte{0x03, 0x78})
+	okm.Write(messageID)
+	okm.Write([]byte{0x01})
+	block, _ := aes.NewCipher(okm.Sum(nil)[:32])
+	gcm, _ := cipher.NewGCM(block)
+
+	headerIV := next(12)
+	if _, err = gcm.Open(nil, headerIV, next(16), header); err != nil {
+		return "", "", fmt.Errorf("header authentication failed: %v", err)
+	}
+	if u32() != 0xFFFFFFFF || u32() != 1 {
+		return "", "", errors.New("expected a single final frame")
+	}
+	iv := next(12)
+	length := u32()
+	aad := &bytes.Buffer{}
+	aad.Write(messageID)
+	aad.WriteString("AWSKMSEncryptionClient Final Frame")
+	binary.Write(aad, binary.BigEndian, uint32(1))
+	binary.Write(aad, binary.BigEndian, uint64(length))
+	content, err := gcm.Open(nil, iv, next(int(length)+16), aad.Bytes())
+	if err != nil {
+		return "", "", fmt.Errorf("frame authentication failed: %v", err)
+	}
+
+	signed := msg[:len(msg)-r.Len()]
+	var sig struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(field(), &sig); err != nil {
+		return "", "", err
+	}
+	x, y := elliptic.UnmarshalCompressed(elliptic.P384(), publicKey)
+	if x == nil {
+		return "", "", errors.New("invalid public key in encryption context")
+	}
+	digest := sha512.Sum384(signed)
+	if !ecdsa.Verify(&ecdsa.PublicKey{Curve: elliptic.P384(), X: x, Y: y}, digest[:], sig.R, sig.S) {
+		return "", "", errors.New("invalid signature")
+	}
+	if r.Len() != 0 {
+		return "", "", errors.New("unexpected data after signature")
+	}
+	return keyID, string(content), nil
+}
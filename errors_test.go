@@ -0,0 +1,87 @@
+package simulator_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+	"github.com/edwardbrowncross/amazon-connect-simulator/module"
+)
+
+var sampleErrors = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0015-000000000001","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0015-000000000002"},{"condition":"Evaluate","conditionType":"Equals","conditionValue":"2","transition":"00000000-0000-4000-0015-000000000003"},{"condition":"Timeout","transition":"00000000-0000-4000-0015-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-0015-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0015-000000000009"}],"parameters":[{"name":"Text","value":"Press 1 or 2."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0015-000000000002","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0015-000000000009"}],"parameters":[{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0015-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0015-000000000001",
+    "metadata":{"name":"Sample errors flow","description":"","type":"contactFlow"}
+}`
+
+func TestBlockErrors(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		press     rune
+		expKind   error
+		expModule flow.ModuleID
+		expType   flow.ModuleType
+		expParam  string
+		expTarget flow.ModuleID
+		expErr    string
+	}{
+		{
+			desc:      "missing parameter",
+			press:     '1',
+			expKind:   module.ErrMissingParameter,
+			expModule: "00000000-0000-4000-0015-000000000002",
+			expType:   flow.ModulePlayPrompt,
+			expParam:  "Text",
+			expErr:    "PlayPrompt block 00000000-0000-4000-0015-000000000002 in Sample errors flow: missing parameter Text",
+		},
+		{
+			desc:      "missing module",
+			press:     '2',
+			expKind:   module.ErrMissingModule,
+			expModule: "00000000-0000-4000-0015-000000000001",
+			expType:   flow.ModuleGetUserInput,
+			expTarget: "00000000-0000-4000-0015-000000000003",
+			expErr:    "GetUserInput block 00000000-0000-4000-0015-000000000001 in Sample errors flow: missing module: 00000000-0000-4000-0015-000000000003",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			sim := newTestSimulator(t, "Sample errors flow", sampleErrors)
+			call := startTestCall(t, sim, CallConfig{})
+			<-call.Caller.O
+			call.Caller.I <- tC.press
+			for range call.Caller.O {
+			}
+			var blockErr *module.Error
+			if !errors.As(call.Err, &blockErr) {
+				t.Fatalf("expected call to end with a block error but got %v", call.Err)
+			}
+			if !errors.Is(call.Err, tC.expKind) {
+				t.Errorf("expected error of kind '%v' but got '%v'", tC.expKind, blockErr.Kind)
+			}
+			if blockErr.ModuleID != tC.expModule {
+				t.Errorf("expected error in block %s but got %s", tC.expModule, blockErr.ModuleID)
+			}
+			if blockErr.ModuleType != tC.expType {
+				t.Errorf("expected error in block of type '%s' but got '%s'", tC.expType, blockErr.ModuleType)
+			}
+			if blockErr.Flow != "Sample errors flow" {
+				t.Errorf("expected error in flow 'Sample errors flow' but got '%s'", blockErr.Flow)
+			}
+			if blockErr.Parameter != tC.expParam {
+				t.Errorf("expected error in parameter '%s' but got '%s'", tC.expParam, blockErr.Parameter)
+			}
+			if blockErr.Target != tC.expTarget {
+				t.Errorf("expected missing block '%s' but got '%s'", tC.expTarget, blockErr.Target)
+			}
+			if call.Err.Error() != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, call.Err.Error())
+			}
+		})
+	}
+}
@@ -21,6 +21,13 @@ const (
 	DisconnectType             = "Disconnect"
 	UpdateContactDataType      = "UpdateContactData"
 	InvokeLambdaType           = "InvokeLambda"
+	RecordingType              = "Recording"
+	MessageType                = "Message"
+	TaskCreatedType            = "TaskCreated"
+	PromptInterruptedType      = "PromptInterrupted"
+	DigitType                  = "Digit"
+	LambdaTimeoutType          = "LambdaTimeout"
+	ContactCreatedType         = "ContactCreated"
 )
 
 // Event is an event describing activity in an ongoing call.
@@ -52,6 +59,10 @@ type PromptEvent struct {
 	Text  string
 	SSML  bool
 	Voice string
+	// Options lists the choices offered by an interactive chat message, such as a list picker.
+	Options []string
+	// Interruptible is true if the caller can cut the prompt short by pressing a key.
+	Interruptible bool
 }
 
 // Type returns PromptType.
@@ -100,8 +111,10 @@ func (e BranchEvent) Type() Type {
 
 // QueueTransferEvent is emitted when a caller is transfered to a queue.
 type QueueTransferEvent struct {
-	QueueARN  string
-	QueueName string
+	QueueARN      string
+	QueueName     string
+	Priority      int
+	AgeAdjustment time.Duration
 }
 
 // Type returns TransferQueueType.
@@ -130,8 +143,21 @@ func (e NumberTransferEvent) Type() Type {
 	return TransferNumberType
 }
 
+// DisconnectReason describes why a contact ended, as recorded in the Connect contact record.
+type DisconnectReason string
+
+// Reasons that a contact can end.
+const (
+	DisconnectCustomer              DisconnectReason = "CUSTOMER_DISCONNECT"
+	DisconnectContactFlow                            = "CONTACT_FLOW_DISCONNECT"
+	DisconnectTelecom                                = "TELECOM_PROBLEM"
+	DisconnectOutboundAttemptFailed                  = "OUTBOUND_ATTEMPT_FAILED"
+)
+
 // DisconnectEvent is emitted when the flow is terminated.
-type DisconnectEvent struct{}
+type DisconnectEvent struct {
+	Reason DisconnectReason
+}
 
 // Type returns DisconnectType.
 func (e DisconnectEvent) Type() Type {
@@ -164,3 +190,92 @@ type InvokeLambdaEvent struct {
 func (e InvokeLambdaEvent) Type() Type {
 	return InvokeLambdaType
 }
+
+// RecordingEvent is emitted when the recording and analytics behavior of the call is set.
+type RecordingEvent struct {
+	Agent     bool
+	Customer  bool
+	Analytics bool
+}
+
+// Type returns RecordingType.
+func (e RecordingEvent) Type() Type {
+	return RecordingType
+}
+
+// MessageEvent is emitted when the customer sends a chat message that the flow receives.
+type MessageEvent struct {
+	DisplayName string
+	Text        string
+}
+
+// Type returns MessageType.
+func (e MessageEvent) Type() Type {
+	return MessageType
+}
+
+// TaskCreatedEvent is emitted when a Create Task block creates a task contact.
+type TaskCreatedEvent struct {
+	ContactID     string
+	Name          string
+	Description   string
+	FlowName      string
+	References    map[string]string
+	ScheduledTime time.Time
+}
+
+// Type returns TaskCreatedType.
+func (e TaskCreatedEvent) Type() Type {
+	return TaskCreatedType
+}
+
+// PromptInterruptedEvent is emitted when the caller presses a key while a prompt is playing (barge-in).
+type PromptInterruptedEvent struct {
+	Text string
+	// Played is how much of the prompt had been played when it was interrupted.
+	Played time.Duration
+	// Length is roughly how long the whole prompt takes to play.
+	Length time.Duration
+}
+
+// Type returns PromptInterruptedType.
+func (e PromptInterruptedEvent) Type() Type {
+	return PromptInterruptedType
+}
+
+// DigitEvent is emitted for each key the caller presses while entering input.
+type DigitEvent struct {
+	Digit rune
+}
+
+// Type returns DigitType.
+func (e DigitEvent) Type() Type {
+	return DigitType
+}
+
+// LambdaTimeoutEvent is emitted when a lambda does not return within the time limit of the block that invoked it.
+// It follows the InvokeLambdaEvent of the invocation, and the block takes its error branch.
+type LambdaTimeoutEvent struct {
+	ARN     string
+	Timeout time.Duration
+}
+
+// Type returns LambdaTimeoutType.
+func (e LambdaTimeoutEvent) Type() Type {
+	return LambdaTimeoutType
+}
+
+// ContactCreatedEvent is emitted when a new contact is created from the call, as happens on a transfer to an external number, a callback or an agent transfer.
+// The new contact is linked to the one it came from by PreviousContactID, and to the first contact in the chain by InitialContactID.
+type ContactCreatedEvent struct {
+	ContactID         string
+	PreviousContactID string
+	InitialContactID  string
+	InitiationMethod  string
+	Channel           string
+}
+
+// Type returns ContactCreatedType.
+func (e ContactCreatedEvent) Type() Type {
+	return ContactCreatedType
+}
@@ -23,6 +23,9 @@ type ModuleParameterNamespace string
 // SystemKey is a valid key that can be dynamically looked up from the connect system.
 type SystemKey string
 
+// EventHook names a point in the life of a contact at which a configured flow is run.
+type EventHook string
+
 // Known types of block.
 const (
 	ModuleStoreUserInput         ModuleType = "StoreUserInput"
@@ -36,6 +39,16 @@ const (
 	ModuleInvokeExternalResource            = "InvokeExternalResource"
 	ModuleCheckHoursOfOperation             = "CheckHoursOfOperation"
 	ModuleSetVoice                          = "SetVoice"
+	ModuleSetEventHook                      = "SetEventHook"
+	ModuleSetLoggingBehavior                = "SetLoggingBehavior"
+	ModuleSetRecordingBehavior              = "SetRecordingBehavior"
+	ModuleChangeRoutingPriority             = "ChangeRoutingPriority"
+	ModuleCheckOutboundCallStatus           = "CheckOutboundCallStatus"
+	ModuleWait                              = "Wait"
+	ModuleSendMessage                       = "SendMessage"
+	ModuleCreateTask                        = "CreateTask"
+	ModuleSetCallbackNumber                 = "SetCallBackNumber"
+	ModuleCreateCallback                    = "CreateCallback"
 )
 
 // Known types of block no longer in use in new flows.
@@ -54,6 +67,8 @@ const (
 	TargetQueue                    = "Queue"
 	TargetDigits                   = "Digits"
 	TargetPhoneNumber              = "PhoneNumber"
+	TargetAgent                    = "Agent"
+	TargetLex                      = "Lex"
 )
 
 // The three places you can look up a dynamic value.
@@ -73,6 +88,15 @@ const (
 	BranchTrue                             = "True"
 	BranchFalse                            = "False"
 	BranchAtCapacity                       = "AtCapacity"
+	BranchCallAnswered                     = "CallAnswered"
+	BranchVoicemailBeep                    = "VoicemailBeep"
+	BranchVoicemailNoBeep                  = "VoicemailNoBeep"
+	BranchNotDetected                      = "NotDetected"
+	BranchCustomerReturned                 = "CustomerReturned"
+	// Store Customer Input and Set Callback Number name their invalid number outputs differently in exported flows.
+	BranchInvalidNumber                    = "InvalidNumber"
+	BranchInvalidPhoneNumber               = "InvalidPhoneNumber"
+	BranchNonDialableNumber                = "NonDialableNumber"
 )
 
 // Operators for Evaluate branches.
@@ -100,6 +124,29 @@ const (
 	SystemChannel                       = "Channel"
 	SystemInstanceARN                   = "InstanceARN"
 	SystemInitiationMethod              = "InitiationMethod"
+	SystemLanguageCode                  = "LanguageCode"
+	SystemAnsweringMachineStatus        = "AnsweringMachineDetectionStatus"
+	SystemTaskName                      = "Name"
+	SystemTaskDescription               = "Description"
+	SystemTaskContactID                 = "Task.ContactId"
+)
+
+// Channels that a contact can come in on.
+const (
+	ChannelVoice = "VOICE"
+	ChannelChat  = "CHAT"
+	ChannelTask  = "TASK"
+)
+
+// Event hooks that can be set with a SetEventHook block.
+const (
+	HookCustomerQueue   EventHook = "CustomerQueue"
+	HookCustomerHold              = "CustomerHold"
+	HookCustomerWhisper           = "CustomerWhisper"
+	HookAgentHold                 = "AgentHold"
+	HookAgentWhisper              = "AgentWhisper"
+	HookOutboundWhisper           = "OutboundWhisper"
+	HookDisconnect                = "CustomerRemaining"
 )
 
 // Flow is the base of the XML structure of an exported flow.
@@ -0,0 +1,57 @@
+package simulator
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// FlowLogEntry is a single line of a contact flow log.
+// It has the same shape as the entries Amazon Connect writes to CloudWatch when logging is enabled by a Set Logging Behavior block.
+type FlowLogEntry struct {
+	ContactID             string                 `json:"ContactId"`
+	ContactFlowName       string                 `json:"ContactFlowName"`
+	ContactFlowModuleType flow.ModuleType        `json:"ContactFlowModuleType"`
+	Identifier            flow.ModuleID          `json:"Identifier"`
+	Parameters            map[string]interface{} `json:"Parameters"`
+	Results               string                 `json:"Results,omitempty"`
+	ExternalResults       map[string]string      `json:"ExternalResults,omitempty"`
+	Timestamp             string                 `json:"Timestamp"`
+}
+
+// flowLogTimeFormat is the timestamp format used in contact flow logs.
+const flowLogTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// flowLogger writes flow log entries from many concurrent calls to a single writer.
+type flowLogger struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+// write outputs a single log entry as a line of JSON.
+func (l *flowLogger) write(e FlowLogEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, err = l.w.Write(append(line, '\n'))
+	return err
+}
+
+// newFlowLogEntry creates the log entry for a block that has just been run.
+func newFlowLogEntry(contactID string, flowName string, m flow.Module, params map[string]interface{}, result flow.ModuleBranchCondition, t time.Time) FlowLogEntry {
+	return FlowLogEntry{
+		ContactID:             contactID,
+		ContactFlowName:       flowName,
+		ContactFlowModuleType: m.Type,
+		Identifier:            m.ID,
+		Parameters:            params,
+		Results:               string(result),
+		Timestamp:             t.UTC().Format(flowLogTimeFormat),
+	}
+}
@@ -0,0 +1,113 @@
+package simulator_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+)
+
+var sampleLogging = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0003-000000000001","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0003-000000000002"}],"parameters":[{"name":"Text","value":"Not logged."},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0003-000000000002","type":"SetLoggingBehavior","branches":[{"condition":"Success","transition":"00000000-0000-4000-0003-000000000003"}],"parameters":[{"name":"LoggingBehavior","value":"Enable"}]},
+        {"id":"00000000-0000-4000-0003-000000000003","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0003-000000000004"},{"condition":"Error","transition":"00000000-0000-4000-0003-000000000004"}],"parameters":[{"name":"Attribute","value":"Customer Number","key":"caller","namespace":"System"}]},
+        {"id":"00000000-0000-4000-0003-000000000004","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0003-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-0003-000000000005"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:greeting"},{"name":"TimeLimit","value":"3"},{"name":"Parameter","key":"caller","value":"caller","namespace":"User Defined"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0003-000000000005","type":"SetLoggingBehavior","branches":[{"condition":"Success","transition":"00000000-0000-4000-0003-000000000006"}],"parameters":[{"name":"LoggingBehavior","value":"Disable"}]},
+        {"id":"00000000-0000-4000-0003-000000000006","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0003-000000000007"}],"parameters":[{"name":"Text","value":"Also not logged."},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0003-000000000007","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0003-000000000001",
+    "metadata":{"name":"Sample logging flow","description":"","type":"contactFlow"}
+}`
+
+func TestFlowLog(t *testing.T) {
+	sim := newTestSimulator(t, "Sample logging flow", sampleLogging)
+	err := sim.RegisterLambda("greeting", func(context.Context, LambdaPayload) (map[string]string, error) {
+		return map[string]string{"greeting": "hello"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+	buf := bytes.NewBuffer(nil)
+	sim.SetFlowLog(buf)
+
+	call := startTestCall(t, sim, CallConfig{
+		Time: time.Date(2020, 06, 22, 13, 00, 0, 0, time.UTC),
+	})
+	for range call.Caller.O {
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log entries but got %d: \n%s", len(lines), buf.String())
+	}
+	entries := make([]FlowLogEntry, len(lines))
+	for i, l := range lines {
+		if err := json.Unmarshal([]byte(l), &entries[i]); err != nil {
+			t.Fatalf("unexpected error parsing log line '%s': %v", l, err)
+		}
+		if entries[i].ContactID == "" {
+			t.Errorf("expected log entry %d to have a contact ID", i)
+		}
+		if entries[i].ContactFlowName != "Sample logging flow" {
+			t.Errorf("expected log entry %d to be from 'Sample logging flow' but got '%s'", i, entries[i].ContactFlowName)
+		}
+		if !strings.HasPrefix(entries[i].Timestamp, "2020-06-22T13:00:") {
+			t.Errorf("expected log entry %d to have a timestamp at the time of the call but got '%s'", i, entries[i].Timestamp)
+		}
+	}
+	expTypes := []string{"SetLoggingBehavior", "SetAttributes", "InvokeExternalResource"}
+	for i, exp := range expTypes {
+		if string(entries[i].ContactFlowModuleType) != exp {
+			t.Errorf("expected log entry %d to be for %s but got %s", i, exp, entries[i].ContactFlowModuleType)
+		}
+	}
+	if v := entries[1].Parameters["Attribute"]; v == nil || v.(map[string]interface{})["caller"] != "+447878123456" {
+		t.Errorf("expected resolved attribute parameter but got %v", entries[1].Parameters)
+	}
+	if v := entries[2].Parameters["Parameter"]; v == nil || v.(map[string]interface{})["caller"] != "+447878123456" {
+		t.Errorf("expected resolved lambda parameter but got %v", entries[2].Parameters)
+	}
+	if entries[2].Results != "Success" {
+		t.Errorf("expected lambda result of Success but got '%s'", entries[2].Results)
+	}
+	if entries[2].ExternalResults["greeting"] != "hello" {
+		t.Errorf("expected lambda external results to include greeting but got %v", entries[2].ExternalResults)
+	}
+}
+
+// brokenWriter fails every write.
+type brokenWriter struct{}
+
+func (brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestFlowLogWriteError(t *testing.T) {
+	sim := newTestSimulator(t, "Sample logging flow", sampleLogging)
+	err := sim.RegisterLambda("greeting", func(context.Context, LambdaPayload) (map[string]string, error) {
+		return map[string]string{"greeting": "hello"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+	sim.SetFlowLog(brokenWriter{})
+
+	call := startTestCall(t, sim, CallConfig{})
+	var prompts []string
+	for p := range call.Caller.O {
+		prompts = append(prompts, p)
+	}
+	if call.Err == nil || !strings.Contains(call.Err.Error(), "disk full") {
+		t.Errorf("expected call to end with the write error but got %v", call.Err)
+	}
+	if len(prompts) != 1 {
+		t.Errorf("expected the call to end at the first logged block but got prompts %v", prompts)
+	}
+}
@@ -11,6 +11,7 @@ type CallerContext struct {
 // If not all characters can be sent, or more characters are required, it errors the test.
 func (tc CallerContext) ToEnter(input string) {
 	tc.t.Helper()
+	tc.expect.listen()
 	tc.expect.cancelReady()
 	for i, r := range input {
 		select {
@@ -30,10 +31,71 @@ func (tc CallerContext) ToEnter(input string) {
 	}
 }
 
+// ToInterrupt starts entering the given string while a prompt is playing, cutting it short (barge-in).
+// The first character interrupts the prompt. The rest are entered as they would be by ToEnter.
+// If no prompt is playing, the input is entered as if by ToEnter.
+func (tc CallerContext) ToInterrupt(input string) {
+	tc.t.Helper()
+	tc.expect.cancelReady()
+	// Waiting for the flow to take every key means a prompt cut short is never mistaken for one still waiting to be interrupted.
+	taken := tc.expect.awaitDigits(len([]rune(input)))
+	for i, r := range input {
+		select {
+		case tc.expect.c.Caller.I <- r:
+			continue
+		case <-time.After(time.Second):
+			tc.t.Errorf("expected to be able to interrupt with input %s, but was only able to send %d characters", input, i)
+			return
+		}
+	}
+	select {
+	case <-taken:
+	case <-tc.expect.finished:
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected input of %s to be taken, but it was not.", input)
+		return
+	}
+	select {
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected input of %s to fill the input, but it did not.", input)
+		tc.expect.readyToggle <- true
+	case <-tc.expect.ready:
+		break
+	}
+}
+
+// ToStartEntering sends the first part of a numeric entry without finishing it.
+// Follow it with ToEnter to enter the rest, or with ToWaitForTimeout to pause until the flow takes what was entered.
+// If not all characters are taken by the flow, it errors the test.
+func (tc CallerContext) ToStartEntering(input string) {
+	tc.t.Helper()
+	tc.expect.listen()
+	tc.expect.cancelReady()
+	taken := tc.expect.awaitDigits(len([]rune(input)))
+	for i, r := range input {
+		select {
+		case tc.expect.c.Caller.I <- r:
+			continue
+		case <-time.After(time.Second):
+			tc.t.Errorf("expected to be able to send input %s, but was only able to send %d characters", input, i)
+			return
+		}
+	}
+	select {
+	case <-taken:
+		tc.expect.entering = true
+	case <-tc.expect.finished:
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected input of %s to be taken, but it was not.", input)
+	}
+}
+
 // ToPress sends the given rune as an option selection at a menu.
+// The caller first hears the prompt to the end.
 // If the press cannot be sent, or more characters are required, it errors the test.
 func (tc CallerContext) ToPress(input rune) {
 	tc.t.Helper()
+	tc.expect.listen()
 	tc.expect.cancelReady()
 	select {
 	case tc.expect.c.Caller.I <- input:
@@ -50,9 +112,53 @@ func (tc CallerContext) ToPress(input rune) {
 	}
 }
 
+// ToSend sends the given chat message from the customer.
+// If the flow is not waiting for a message, or more input is required, it errors the test.
+func (tc CallerContext) ToSend(message string) {
+	tc.t.Helper()
+	tc.expect.cancelReady()
+	select {
+	case tc.expect.c.Caller.T <- message:
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected to be able to send message '%s', but the flow was not ready for input", message)
+		return
+	}
+	select {
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected message '%s' to fill the input, but it did not.", message)
+		tc.expect.readyToggle <- true
+	case <-tc.expect.ready:
+		break
+	}
+}
+
+// ToSay speaks the given words on a call.
+// Blocks that only take key presses do not hear speech, and time out.
+// If the flow is not waiting for input, it errors the test.
+func (tc CallerContext) ToSay(utterance string) {
+	tc.t.Helper()
+	tc.expect.listen()
+	tc.expect.cancelReady()
+	select {
+	case tc.expect.c.Caller.T <- utterance:
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected to be able to say '%s', but the flow was not ready for input", utterance)
+		return
+	}
+	select {
+	case <-time.After(time.Second):
+		tc.t.Errorf("expected saying '%s' to fill the input, but it did not.", utterance)
+		tc.expect.readyToggle <- true
+	case <-tc.expect.ready:
+		break
+	}
+}
+
 // ToWaitForTimeout waits for the current input block to time out.
+// Part way through an entry, it waits for the inter-digit timeout, and the flow takes what was entered so far.
 func (tc CallerContext) ToWaitForTimeout() {
 	tc.t.Helper()
+	tc.expect.listen()
 	tc.expect.cancelReady()
 	select {
 	case <-time.After(time.Second):
@@ -61,3 +167,11 @@ func (tc CallerContext) ToWaitForTimeout() {
 	case tc.expect.c.Caller.I <- 'T':
 	}
 }
+
+// ToHangUp simulates the caller putting the phone down.
+// If the flow has set a disconnect flow, that flow runs to completion without the caller.
+func (tc CallerContext) ToHangUp() {
+	tc.t.Helper()
+	tc.expect.cancelReady()
+	tc.expect.c.Hangup()
+}
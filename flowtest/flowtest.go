@@ -17,7 +17,11 @@ type Expect struct {
 	evts        []event.Event
 	ready       <-chan bool
 	readyToggle chan<- bool
+	release     chan<- chan bool
+	digitWaits  chan<- digitWaiter
+	finished    <-chan struct{}
 	Terminated  bool
+	entering    bool
 	mutex       sync.RWMutex
 	nevers      []matcher
 }
@@ -28,39 +32,109 @@ func New(t *testing.T, c *simulator.Call) *Expect {
 	buffer := make(chan event.Event, 64)
 	c.Subscribe(buffer)
 	readyVal, readyToggle := toggleChannel()
+	release := make(chan chan bool)
+	digitWaits := make(chan digitWaiter)
+	finished := make(chan struct{})
 
 	th := Expect{
 		t:           t,
 		c:           c,
 		ready:       readyVal,
 		readyToggle: readyToggle,
+		release:     release,
+		digitWaits:  digitWaits,
+		finished:    finished,
 		evts:        make([]event.Event, 0),
 		nevers:      make([]matcher, 0),
 	}
 
 	go func() {
+		// out is nil while an interruptible prompt is held, so that the caller can interrupt it.
+		out := c.Caller.O
+		// digits counts the key presses taken by the flow, so that callers can wait for their input to be used.
+		digits := 0
+		waiting := []digitWaiter{}
+		handle := func(evt event.Event, ok bool) bool {
+			if !ok {
+				close(readyToggle)
+				close(finished)
+				th.Terminated = true
+				return false
+			}
+			th.mutex.Lock()
+			th.evts = append(th.evts, evt)
+			th.runNevers(evt)
+			th.mutex.Unlock()
+			switch evt.Type() {
+			case event.PromptType:
+				if evt.(event.PromptEvent).Interruptible {
+					out = nil
+					readyToggle <- true
+					break
+				}
+				out = c.Caller.O
+				readyToggle <- false
+			case event.PromptInterruptedType:
+				out = c.Caller.O
+				readyToggle <- false
+			case event.DigitType:
+				digits++
+				stillWaiting := waiting[:0]
+				for _, w := range waiting {
+					if w.count <= digits {
+						close(w.done)
+						continue
+					}
+					stillWaiting = append(stillWaiting, w)
+				}
+				waiting = stillWaiting
+				readyToggle <- false
+			case event.DisconnectType, event.InputType, event.TransferQueueType:
+				readyToggle <- true
+			default:
+				readyToggle <- false
+			}
+			return true
+		}
 		for {
+			// Events are handled before output, so a prompt is never heard before it is known to be interruptible.
 			select {
-			case <-c.Caller.O:
 			case evt, ok := <-buffer:
-				if !ok {
-					close(readyToggle)
-					th.Terminated = true
+				if !handle(evt, ok) {
 					return
 				}
-				th.mutex.Lock()
-				th.evts = append(th.evts, evt)
-				th.runNevers(evt)
-				th.mutex.Unlock()
-				switch evt.Type() {
-				case event.DisconnectType, event.InputType, event.TransferQueueType:
-					readyToggle <- true
-				case event.ModuleType:
-					// fmt.Println(evt.(event.ModuleEvent).ModuleType)
-					fallthrough
-				default:
+				continue
+			default:
+			}
+			select {
+			case <-out:
+			case released := <-release:
+				held := out == nil
+				if held {
 					readyToggle <- false
 				}
+				for out == nil {
+					select {
+					case <-c.Caller.O:
+						out = c.Caller.O
+					case evt, ok := <-buffer:
+						if !handle(evt, ok) {
+							return
+						}
+					}
+				}
+				released <- held
+			case w := <-digitWaits:
+				w.count += digits
+				if w.count <= digits {
+					close(w.done)
+					break
+				}
+				waiting = append(waiting, w)
+			case evt, ok := <-buffer:
+				if !handle(evt, ok) {
+					return
+				}
 			}
 		}
 	}()
@@ -73,6 +147,46 @@ func (th *Expect) readEvents() (ok bool) {
 	return
 }
 
+// listen lets the caller hear any interruptible prompt to the end, and waits until the flow is ready for input.
+// Part way through an entry, the flow is already listening, so there is nothing to wait for.
+func (th *Expect) listen() {
+	if th.entering {
+		th.entering = false
+		return
+	}
+	for th.readEvents() {
+		released := make(chan bool, 1)
+		select {
+		case th.release <- released:
+		case <-th.finished:
+			return
+		}
+		select {
+		case held := <-released:
+			if !held {
+				return
+			}
+		case <-th.finished:
+			return
+		}
+	}
+}
+
+// awaitDigits returns a channel that is closed once the flow has taken n more key presses.
+func (th *Expect) awaitDigits(n int) <-chan struct{} {
+	done := make(chan struct{})
+	select {
+	case th.digitWaits <- digitWaiter{count: n, done: done}:
+	case <-th.finished:
+	}
+	return done
+}
+
+type digitWaiter struct {
+	count int
+	done  chan<- struct{}
+}
+
 func (th *Expect) cancelReady() {
 	if th.Terminated {
 		return
@@ -158,6 +272,16 @@ func (th *Expect) Attributes() AttributesContext {
 	return AttributesContext{th.newTestContext()}
 }
 
+// Recording offers assertions on call recording and analytics behavior.
+func (th *Expect) Recording() RecordingContext {
+	return RecordingContext{th.newTestContext()}
+}
+
+// Task offers assertions on tasks created by the flow.
+func (th *Expect) Task() TaskContext {
+	return TaskContext{th.newTestContext()}
+}
+
 // To accepts an assertion function that will be run immediately.
 // This can be use for modularising tests while maintaining the fluent interface.
 func (th *Expect) To(assert func(expect *Expect)) {
@@ -1,7 +1,9 @@
 package flowtest
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -68,6 +70,12 @@ func (tc LambdaContext) ToSucceed() {
 	tc.run(lambdaSuccessMatcher{})
 }
 
+// ToTimeOut asserts that a lambda was invoked and did not return within the time limit of its block.
+func (tc LambdaContext) ToTimeOut() {
+	tc.t.Helper()
+	tc.run(lambdaTimedOutMatcher{})
+}
+
 // Not negates the meaning of the following assertion.
 func (tc LambdaContext) Not() LambdaContext {
 	tc.not()
@@ -263,3 +271,26 @@ func (m lambdaFailureMatcher) match(evt event.Event) (match bool, pass bool, got
 func (m lambdaFailureMatcher) expected() string {
 	return "to fail to invoke lambda"
 }
+
+type lambdaTimedOutMatcher struct{}
+
+func (m lambdaTimedOutMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.InvokeLambdaType {
+		return false, false, ""
+	}
+	e := evt.(event.InvokeLambdaEvent)
+	match = true
+	pass = errors.Is(e.Error, context.DeadlineExceeded)
+	if e.ResponseError != nil {
+		got = e.ResponseError.Error()
+	} else if e.Error != nil {
+		got = e.Error.Error()
+	} else {
+		got = "returned in time"
+	}
+	return
+}
+
+func (m lambdaTimedOutMatcher) expected() string {
+	return "to time out"
+}
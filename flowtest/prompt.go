@@ -30,6 +30,12 @@ func (tc PromptContext) WithVoice(voice string) PromptContext {
 	return tc
 }
 
+// WithOptions adds a pending assertion that the matching prompt is an interactive chat message offering exactly the given options, in order.
+func (tc PromptContext) WithOptions(options ...string) PromptContext {
+	tc.addMatcher(promptOptionsMatcher{options})
+	return tc
+}
+
 // ToContain asserts that the prompt contains the given string.
 func (tc PromptContext) ToContain(msg string) {
 	tc.t.Helper()
@@ -42,6 +48,12 @@ func (tc PromptContext) ToEqual(msg string) {
 	tc.run(promptExactMatcher{msg})
 }
 
+// ToBeInterrupted asserts that the prompt exactly equal to the given string is cut short by the caller pressing a key.
+func (tc PromptContext) ToBeInterrupted(msg string) {
+	tc.t.Helper()
+	tc.run(promptInterruptedMatcher{msg})
+}
+
 // ToPlay asserts that any prompt is heard.
 func (tc PromptContext) ToPlay() {
 	tc.t.Helper()
@@ -151,3 +163,49 @@ func (m promptVoiceMatcher) match(evt event.Event) (match bool, pass bool, got s
 func (m promptVoiceMatcher) expected() string {
 	return fmt.Sprintf("read in the %s voice", m.voice)
 }
+
+type promptOptionsMatcher struct {
+	options []string
+}
+
+func (m promptOptionsMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.PromptType {
+		return false, false, ""
+	}
+	e := evt.(event.PromptEvent)
+	match = true
+	got = fmt.Sprintf("with options [%s]", strings.Join(e.Options, ", "))
+	if len(e.Options) != len(m.options) {
+		return
+	}
+	for i, o := range m.options {
+		if e.Options[i] != o {
+			return
+		}
+	}
+	pass = true
+	return
+}
+
+func (m promptOptionsMatcher) expected() string {
+	return fmt.Sprintf("with options [%s]", strings.Join(m.options, ", "))
+}
+
+type promptInterruptedMatcher struct {
+	text string
+}
+
+func (m promptInterruptedMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.PromptInterruptedType {
+		return false, false, ""
+	}
+	e := evt.(event.PromptInterruptedEvent)
+	match = true
+	got = fmt.Sprintf("%s (interrupted after %v)", e.Text, e.Played)
+	pass = bool(e.Text == m.text)
+	return
+}
+
+func (m promptInterruptedMatcher) expected() string {
+	return fmt.Sprintf("prompt '%s' to be interrupted", m.text)
+}
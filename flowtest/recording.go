@@ -0,0 +1,196 @@
+package flowtest
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// RecordingContext is returned from Expect.Recording()
+type RecordingContext struct {
+	testContext
+}
+
+// WithAgent adds an assertion that the matching recording behavior also records the agent.
+func (tc RecordingContext) WithAgent() RecordingContext {
+	tc.addMatcher(recordingParticipantMatcher{agent: true})
+	return tc
+}
+
+// WithCustomer adds an assertion that the matching recording behavior also records the customer.
+func (tc RecordingContext) WithCustomer() RecordingContext {
+	tc.addMatcher(recordingParticipantMatcher{agent: false})
+	return tc
+}
+
+// WithAnalytics adds an assertion that the matching recording behavior also enables Contact Lens analytics.
+func (tc RecordingContext) WithAnalytics() RecordingContext {
+	tc.addMatcher(recordingAnalyticsMatcher{})
+	return tc
+}
+
+// ToStart asserts that recording is switched on for the agent, customer or both.
+func (tc RecordingContext) ToStart() {
+	tc.t.Helper()
+	tc.run(recordingOnMatcher{true})
+}
+
+// ToStop asserts that recording is switched off for both agent and customer.
+func (tc RecordingContext) ToStop() {
+	tc.t.Helper()
+	tc.run(recordingOnMatcher{false})
+}
+
+// ToBeOnDuringInput asserts that the call is being recorded when the caller is asked for input.
+// Its main use is with Never(), to check that input is not captured while recording.
+// Set it up before the call starts recording so that it can follow changes to the recording behavior.
+func (tc RecordingContext) ToBeOnDuringInput() {
+	tc.t.Helper()
+	tc.run(recordingDuringInputMatcher{state: &recordingInputState{}})
+}
+
+// ToBeOnDuringStoredInput is like ToBeOnDuringInput, but only considers input taken by Store Customer Input blocks (such as card numbers).
+func (tc RecordingContext) ToBeOnDuringStoredInput() {
+	tc.t.Helper()
+	tc.run(recordingDuringInputMatcher{storedOnly: true, state: &recordingInputState{}})
+}
+
+// Not negates the meaning of the following assertion.
+func (tc RecordingContext) Not() RecordingContext {
+	tc.not()
+	return tc
+}
+
+// Never asserts that the following assertions will never match for the durtion of the call.
+func (tc RecordingContext) Never() RecordingContext {
+	tc.never()
+	return tc
+}
+
+// Unordered suspends the implicit assertion that events occur in the flow in the order you assert them in your tests.
+func (tc RecordingContext) Unordered() RecordingContext {
+	tc.unordered()
+	return tc
+}
+
+func formatRecording(e event.RecordingEvent) string {
+	switch {
+	case e.Agent && e.Customer:
+		return "recording agent and customer"
+	case e.Agent:
+		return "recording agent"
+	case e.Customer:
+		return "recording customer"
+	default:
+		return "not recording"
+	}
+}
+
+type recordingOnMatcher struct {
+	on bool
+}
+
+func (m recordingOnMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.RecordingType {
+		return false, false, ""
+	}
+	e := evt.(event.RecordingEvent)
+	match = true
+	got = formatRecording(e)
+	pass = bool((e.Agent || e.Customer) == m.on)
+	return
+}
+
+func (m recordingOnMatcher) expected() string {
+	if m.on {
+		return "to start recording"
+	}
+	return "to stop recording"
+}
+
+type recordingParticipantMatcher struct {
+	agent bool
+}
+
+func (m recordingParticipantMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.RecordingType {
+		return false, false, ""
+	}
+	e := evt.(event.RecordingEvent)
+	match = true
+	got = formatRecording(e)
+	pass = bool((m.agent && e.Agent) || (!m.agent && e.Customer))
+	return
+}
+
+func (m recordingParticipantMatcher) expected() string {
+	if m.agent {
+		return "recording the agent"
+	}
+	return "recording the customer"
+}
+
+type recordingAnalyticsMatcher struct{}
+
+func (m recordingAnalyticsMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.RecordingType {
+		return false, false, ""
+	}
+	e := evt.(event.RecordingEvent)
+	match = true
+	if e.Analytics {
+		got = "with analytics"
+	} else {
+		got = "without analytics"
+	}
+	pass = e.Analytics
+	return
+}
+
+func (m recordingAnalyticsMatcher) expected() string {
+	return "with analytics enabled"
+}
+
+type recordingInputState struct {
+	recording bool
+	module    flow.ModuleType
+}
+
+type recordingDuringInputMatcher struct {
+	storedOnly bool
+	state      *recordingInputState
+}
+
+func (m recordingDuringInputMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	switch evt.Type() {
+	case event.RecordingType:
+		e := evt.(event.RecordingEvent)
+		m.state.recording = e.Agent || e.Customer
+		return false, false, ""
+	case event.ModuleType:
+		m.state.module = evt.(event.ModuleEvent).ModuleType
+		return false, false, ""
+	case event.InputType:
+		if m.storedOnly && m.state.module != flow.ModuleStoreUserInput {
+			return false, false, ""
+		}
+		match = true
+		pass = m.state.recording
+		if pass {
+			got = fmt.Sprintf("recording during input to %s", m.state.module)
+		} else {
+			got = fmt.Sprintf("not recording during input to %s", m.state.module)
+		}
+		return
+	default:
+		return false, false, ""
+	}
+}
+
+func (m recordingDuringInputMatcher) expected() string {
+	if m.storedOnly {
+		return "to be recording while storing customer input"
+	}
+	return "to be recording while taking customer input"
+}
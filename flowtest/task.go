@@ -0,0 +1,101 @@
+package flowtest
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+// TaskContext is returned from Expect.Task()
+type TaskContext struct {
+	testContext
+}
+
+// WithReference adds an assertion that the task carries a reference with the given name and value.
+func (tc TaskContext) WithReference(name string, value string) TaskContext {
+	tc.addMatcher(taskReferenceMatcher{name, value})
+	return tc
+}
+
+// InFlow adds an assertion that the task runs the flow with the given name.
+func (tc TaskContext) InFlow(named string) TaskContext {
+	tc.addMatcher(taskFlowMatcher{named})
+	return tc
+}
+
+// ToBeCreated asserts that a task with the given name was created.
+func (tc TaskContext) ToBeCreated(named string) {
+	tc.t.Helper()
+	tc.run(taskCreatedMatcher{named})
+}
+
+// Never asserts that the following assertions will never match for the durtion of the call.
+func (tc TaskContext) Never() TaskContext {
+	tc.never()
+	return tc
+}
+
+// Unordered suspends the implicit assertion that events occur in the flow in the order you assert them in your tests.
+func (tc TaskContext) Unordered() TaskContext {
+	tc.unordered()
+	return tc
+}
+
+type taskCreatedMatcher struct {
+	name string
+}
+
+func (m taskCreatedMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.TaskCreatedType {
+		return false, false, ""
+	}
+	e := evt.(event.TaskCreatedEvent)
+	match = true
+	got = e.Name
+	pass = bool(e.Name == m.name)
+	return
+}
+
+func (m taskCreatedMatcher) expected() string {
+	return fmt.Sprintf("task '%s' to be created", m.name)
+}
+
+type taskFlowMatcher struct {
+	flowName string
+}
+
+func (m taskFlowMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.TaskCreatedType {
+		return false, false, ""
+	}
+	e := evt.(event.TaskCreatedEvent)
+	match = true
+	got = e.FlowName
+	pass = bool(e.FlowName == m.flowName)
+	return
+}
+
+func (m taskFlowMatcher) expected() string {
+	return fmt.Sprintf("in flow '%s'", m.flowName)
+}
+
+type taskReferenceMatcher struct {
+	name  string
+	value string
+}
+
+func (m taskReferenceMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.TaskCreatedType {
+		return false, false, ""
+	}
+	e := evt.(event.TaskCreatedEvent)
+	match = true
+	got = fmt.Sprintf("%s: %s", m.name, e.References[m.name])
+	val, ok := e.References[m.name]
+	pass = bool(ok && val == m.value)
+	return
+}
+
+func (m taskReferenceMatcher) expected() string {
+	return fmt.Sprintf("with reference %s: %s", m.name, m.value)
+}
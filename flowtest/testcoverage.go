@@ -34,16 +34,13 @@ func NewCoverageReporter(s *simulator.Simulator) CoverageReporter {
 // Any route covered by the call after it is passed to Track is considered tested.
 func (cr *CoverageReporter) Track(call *simulator.Call) {
 	evts := make(chan event.Event)
-	call.Subscribe(evts)
+	call.Subscribe(evts, simulator.OfType(event.BranchType))
 	go func() {
 		for {
 			evt, ok := <-evts
 			if !ok {
 				return
 			}
-			if evt.Type() != event.BranchType {
-				continue
-			}
 			cr.add(evt.(event.BranchEvent))
 		}
 	}()
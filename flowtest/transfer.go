@@ -11,6 +11,12 @@ type TransferContext struct {
 	testContext
 }
 
+// WithPriority adds an assertion that the caller is placed in the queue with the given routing priority.
+func (tc TransferContext) WithPriority(priority int) TransferContext {
+	tc.addMatcher(queuePriorityMatcher{priority})
+	return tc
+}
+
 // ToQueue asserts that the caller is transferred to a queue with the given name.
 func (tc TransferContext) ToQueue(named string) {
 	tc.t.Helper()
@@ -60,6 +66,25 @@ func (m queueTransferMatcher) expected() string {
 	return fmt.Sprintf("to be transfered to queue '%s'", m.queueName)
 }
 
+type queuePriorityMatcher struct {
+	priority int
+}
+
+func (m queuePriorityMatcher) match(evt event.Event) (match bool, pass bool, got string) {
+	if evt.Type() != event.TransferQueueType {
+		return false, false, ""
+	}
+	e := evt.(event.QueueTransferEvent)
+	match = true
+	got = fmt.Sprintf("priority %d", e.Priority)
+	pass = bool(e.Priority == m.priority)
+	return
+}
+
+func (m queuePriorityMatcher) expected() string {
+	return fmt.Sprintf("with routing priority %d", m.priority)
+}
+
 type flowTransferMatcher struct {
 	flowName string
 }
@@ -0,0 +1,73 @@
+package simulator
+
+import (
+	"errors"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// Fork starts an independent copy of a call in progress, so that it can be driven differently from the original.
+// Forking a call that is waiting for the caller saves repeating everything that led up to that point.
+//
+// The fork has a copy of the call's attributes, its settings (such as event hooks and routing priority) and its time within the call.
+// It is a separate contact, so it has its own contact ID, but keeps the initial contact ID of the original.
+// It starts at the beginning of the block the original is in, which is run again: a menu the original is waiting at plays its prompt again.
+// Loop guards count blocks from the start of the fork.
+//
+// The fork has no subscribers. If subscribe is not nil, it is called with the fork before the fork starts running, so that events can be subscribed to without missing any.
+// It errors if the call has ended.
+func (c *Call) Fork(subscribe func(fork *Call)) (*Call, error) {
+	select {
+	case <-c.ended:
+		return nil, errors.New("cannot fork a call that has ended")
+	default:
+	}
+	c.stateMutex.RLock()
+	if c.module == nil {
+		c.stateMutex.RUnlock()
+		return nil, errors.New("cannot fork a call that is not in a block")
+	}
+	fork := newCall(CallConfig{Time: c.Time}, c.sc)
+	fork.ctx = c.ctx
+	fork.displayName = c.displayName
+	fork.bargeInAfter = c.bargeInAfter
+	fork.logging = c.logging
+	fork.priority = c.priority
+	fork.ageAdjust = c.ageAdjust
+	fork.elapsed = c.elapsed
+	if c.recording != nil {
+		r := *c.recording
+		fork.recording = &r
+	}
+	if c.task != nil {
+		t := *c.task
+		t.references = make(map[string]string, len(c.task.references))
+		for k, v := range c.task.references {
+			t.references[k] = v
+		}
+		fork.task = &t
+	}
+	for k, v := range c.hooks {
+		fork.hooks[k] = v
+	}
+	for k, v := range c.External {
+		fork.External[k] = v
+	}
+	for k, v := range c.ContactData {
+		fork.ContactData[k] = v
+	}
+	contactID := fork.System[flow.SystemContactID]
+	fork.System = make(map[flow.SystemKey]string, len(c.System))
+	for k, v := range c.System {
+		fork.System[k] = v
+	}
+	fork.System[flow.SystemContactID] = contactID
+	flows := append([]flow.ModuleID{c.module.ID}, c.remaining...)
+	c.stateMutex.RUnlock()
+
+	if subscribe != nil {
+		subscribe(fork)
+	}
+	fork.start(c.sc, flows...)
+	return fork, nil
+}
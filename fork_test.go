@@ -0,0 +1,92 @@
+package simulator_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleFork = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0016-000000000001","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0016-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0016-000000000009"}],"parameters":[{"name":"Attribute","value":"true","key":"authenticated","namespace":null}]},
+        {"id":"00000000-0000-4000-0016-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0016-000000000003"},{"condition":"Evaluate","conditionType":"Equals","conditionValue":"2","transition":"00000000-0000-4000-0016-000000000004"},{"condition":"Timeout","transition":"00000000-0000-4000-0016-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-0016-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0016-000000000009"}],"parameters":[{"name":"Text","value":"Press 1 for your balance or 2 to make a payment."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0016-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0016-000000000009"}],"parameters":[{"name":"Text","value":"Your balance is ten pounds.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0016-000000000004","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0016-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-0016-000000000009"}],"parameters":[{"name":"Attribute","value":"payment","key":"intent","namespace":null}]},
+        {"id":"00000000-0000-4000-0016-000000000005","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0016-000000000009"}],"parameters":[{"name":"Text","value":"Transferring you to payments.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0016-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0016-000000000001",
+    "metadata":{"name":"Sample fork flow","description":"","type":"contactFlow"}
+}`
+
+func TestFork(t *testing.T) {
+	sim := newTestSimulator(t, "Sample fork flow", sampleFork)
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	call := startTestCall(t, sim, CallConfig{
+		Time: start,
+	})
+	if p := <-call.Caller.O; p != "Press 1 for your balance or 2 to make a payment." {
+		t.Fatalf("expected menu prompt but got '%s'", p)
+	}
+
+	first := make(chan event.Event, 1)
+	fork, err := call.Fork(func(fork *Call) {
+		evts := make(chan event.Event, 64)
+		fork.Subscribe(evts)
+		go func() {
+			first <- <-evts
+			for range evts {
+			}
+		}()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error forking call: %v", err)
+	}
+	expect := flowtest.New(t, fork)
+	expect.Prompt().ToEqual("Press 1 for your balance or 2 to make a payment.")
+	if evt, ok := (<-first).(event.ModuleEvent); !ok || evt.ID != "00000000-0000-4000-0016-000000000002" {
+		t.Errorf("expected fork to start by entering the menu block but got %v", evt)
+	}
+	expect.Caller().ToEnter("2")
+	expect.Prompt().ToEqual("Transferring you to payments.")
+
+	// The original is unaffected by the fork.
+	call.Caller.I <- '1'
+	if p := <-call.Caller.O; p != "Your balance is ten pounds." {
+		t.Errorf("expected balance prompt but got '%s'", p)
+	}
+	for range call.Caller.O {
+	}
+	for range fork.Caller.O {
+	}
+
+	if fork.ContactData["authenticated"] != "true" {
+		t.Error("expected fork to keep attributes set before it was forked")
+	}
+	if fork.ContactData["intent"] != "payment" {
+		t.Error("expected fork to set its own attributes")
+	}
+	if _, ok := call.ContactData["intent"]; ok {
+		t.Error("expected attributes set by the fork not to be set on the original")
+	}
+	if fork.System[flow.SystemContactID] == call.System[flow.SystemContactID] {
+		t.Errorf("expected fork to have its own contact ID")
+	}
+	if fork.System[flow.SystemInitialContactID] != call.System[flow.SystemInitialContactID] {
+		t.Errorf("expected fork to keep the initial contact ID of the original")
+	}
+	if fork.Time.Before(start) || fork.Time.After(start.Add(time.Minute)) {
+		t.Errorf("expected fork to start shortly after %v but it started at %v", start, fork.Time)
+	}
+	if fork.Err != nil {
+		t.Errorf("unexpected error in fork: %v", fork.Err)
+	}
+
+	if _, err := call.Fork(nil); err == nil {
+		t.Error("expected error forking a call that has ended")
+	}
+}
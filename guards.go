@@ -0,0 +1,118 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// LoopGuards limit how far a call may run, so that flows that loop forever come to an end, as they eventually would in Amazon Connect.
+// A limit of zero is no limit.
+type LoopGuards struct {
+	// MaxBlocks is the most blocks a call may run, counting each time a block is run.
+	MaxBlocks int
+	// MaxVisits is the most times a call may run any one block.
+	MaxVisits int
+	// MaxDuration is the most simulated time a call may spend in flows.
+	// Time passes as prompts are played and as the flow waits for the caller (a T entered by the caller takes the whole timeout).
+	MaxDuration time.Duration
+}
+
+// DefaultLoopGuards are the guards used unless SetLoopGuards is called.
+var DefaultLoopGuards = LoopGuards{
+	MaxBlocks: 10000,
+}
+
+// LoopGuard names one of the LoopGuards.
+type LoopGuard string
+
+// Loop guards.
+const (
+	GuardMaxBlocks   LoopGuard = "MaxBlocks"
+	GuardMaxVisits             = "MaxVisits"
+	GuardMaxDuration           = "MaxDuration"
+)
+
+// LoopError ends a call that trips one of its loop guards.
+type LoopError struct {
+	// Guard is the guard that was tripped.
+	Guard LoopGuard
+	// Module is the block that was about to run.
+	Module flow.ModuleID
+	// Flow is the name of the flow Module is in.
+	Flow string
+	// Path is the blocks run since Module was last run, starting and ending with Module.
+	// It is empty if Module had not been run before.
+	Path []flow.ModuleID
+	// Blocks is the number of blocks the call had run.
+	Blocks int
+	// Elapsed is the simulated time the call had spent in flows.
+	Elapsed time.Duration
+}
+
+func (e *LoopError) Error() string {
+	msg := fmt.Sprintf("flow loop: %s exceeded at block %s in %s after %d blocks (%v)", e.Guard, e.Module, e.Flow, e.Blocks, e.Elapsed)
+	if len(e.Path) == 0 {
+		return msg
+	}
+	path := make([]string, len(e.Path))
+	for i, id := range e.Path {
+		path[i] = string(id)
+	}
+	return fmt.Sprintf("%s: %s", msg, strings.Join(path, " -> "))
+}
+
+// SetLoopGuards sets the limits that end calls stuck in a loop (see LoopGuards).
+// A call that trips a guard ends with a *LoopError.
+func (cs *Simulator) SetLoopGuards(guards LoopGuards) {
+	cs.guards = guards
+}
+
+// loopTracker records the blocks run by a call to check them against its loop guards.
+type loopTracker struct {
+	guards  LoopGuards
+	history []flow.ModuleID
+	// last is the position in history that each block was last run at.
+	last map[flow.ModuleID]int
+	// visits is the number of times each block has been run.
+	visits map[flow.ModuleID]int
+}
+
+func newLoopTracker(guards LoopGuards) *loopTracker {
+	return &loopTracker{
+		guards: guards,
+		last:   map[flow.ModuleID]int{},
+		visits: map[flow.ModuleID]int{},
+	}
+}
+
+// visit records that a block is about to run, returning an error if doing so would trip a guard.
+func (t *loopTracker) visit(id flow.ModuleID, elapsed time.Duration) *LoopError {
+	var guard LoopGuard
+	switch {
+	case t.guards.MaxBlocks > 0 && len(t.history) >= t.guards.MaxBlocks:
+		guard = GuardMaxBlocks
+	case t.guards.MaxVisits > 0 && t.visits[id] >= t.guards.MaxVisits:
+		guard = GuardMaxVisits
+	case t.guards.MaxDuration > 0 && elapsed > t.guards.MaxDuration:
+		guard = GuardMaxDuration
+	}
+	if guard != "" {
+		err := &LoopError{
+			Guard:   guard,
+			Module:  id,
+			Blocks:  len(t.history),
+			Elapsed: elapsed,
+		}
+		if i, ok := t.last[id]; ok {
+			err.Path = append(append(err.Path, t.history[i:]...), id)
+		}
+		return err
+	}
+	t.last[id] = len(t.history)
+	t.visits[id]++
+	t.history = append(t.history, id)
+	return nil
+}
@@ -0,0 +1,145 @@
+package simulator_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+var sampleLoop = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0014-000000000001","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0014-000000000009"},{"condition":"Timeout","transition":"00000000-0000-4000-0014-000000000002"},{"condition":"NoMatch","transition":"00000000-0000-4000-0014-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0014-000000000009"}],"parameters":[{"name":"Text","value":"Press 1 to continue."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0014-000000000002","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0014-000000000001"}],"parameters":[{"name":"Text","value":"Sorry, I didn't get that.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0014-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0014-000000000001",
+    "metadata":{"name":"Sample loop flow","description":"","type":"contactFlow"}
+}`
+
+var sampleEndlessLoop = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0014-000000000011","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0014-000000000012"}],"parameters":[{"name":"Text","value":"Please hold.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0014-000000000012","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0014-000000000011"},{"condition":"Error","transition":"00000000-0000-4000-0014-000000000011"}],"parameters":[{"name":"Attribute","value":"true","key":"held","namespace":null}]}
+    ],
+    "start":"00000000-0000-4000-0014-000000000011",
+    "metadata":{"name":"Sample endless loop flow","description":"","type":"contactFlow"}
+}`
+
+func TestLoopGuards(t *testing.T) {
+	start := func(t *testing.T, flowName string, guards *LoopGuards) *Call {
+		sim := newTestSimulator(t, flowName, sampleLoop, sampleEndlessLoop)
+		if guards != nil {
+			sim.SetLoopGuards(*guards)
+		}
+		return startTestCall(t, sim, CallConfig{})
+	}
+	// timeOut lets every menu time out until the call ends.
+	timeOut := func(call *Call) {
+		for p := range call.Caller.O {
+			if p == "Press 1 to continue." {
+				call.Caller.I <- 'T'
+			}
+		}
+	}
+	loopPath := []flow.ModuleID{
+		"00000000-0000-4000-0014-000000000001",
+		"00000000-0000-4000-0014-000000000002",
+		"00000000-0000-4000-0014-000000000001",
+	}
+
+	testCases := []struct {
+		desc      string
+		flow      string
+		guards    *LoopGuards
+		expGuard  LoopGuard
+		expBlocks int
+		expPath   []flow.ModuleID
+	}{
+		{
+			desc:      "max visits",
+			flow:      "Sample loop flow",
+			guards:    &LoopGuards{MaxVisits: 3},
+			expGuard:  GuardMaxVisits,
+			expBlocks: 6,
+			expPath:   loopPath,
+		},
+		{
+			desc:      "max blocks",
+			flow:      "Sample loop flow",
+			guards:    &LoopGuards{MaxBlocks: 5},
+			expGuard:  GuardMaxBlocks,
+			expBlocks: 5,
+			expPath: []flow.ModuleID{
+				"00000000-0000-4000-0014-000000000002",
+				"00000000-0000-4000-0014-000000000001",
+				"00000000-0000-4000-0014-000000000002",
+			},
+		},
+		{
+			// Each time round takes 1.6 seconds for the menu, the 5 second timeout and 2 seconds for the apology.
+			desc:      "max duration",
+			flow:      "Sample loop flow",
+			guards:    &LoopGuards{MaxDuration: 16 * time.Second},
+			expGuard:  GuardMaxDuration,
+			expBlocks: 4,
+			expPath:   loopPath,
+		},
+		{
+			desc:      "default",
+			flow:      "Sample endless loop flow",
+			expGuard:  GuardMaxBlocks,
+			expBlocks: DefaultLoopGuards.MaxBlocks,
+			expPath: []flow.ModuleID{
+				"00000000-0000-4000-0014-000000000011",
+				"00000000-0000-4000-0014-000000000012",
+				"00000000-0000-4000-0014-000000000011",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			call := start(t, tC.flow, tC.guards)
+			timeOut(call)
+			var loopErr *LoopError
+			if !errors.As(call.Err, &loopErr) {
+				t.Fatalf("expected call to end with a loop error but got %v", call.Err)
+			}
+			if loopErr.Guard != tC.expGuard {
+				t.Errorf("expected %s guard to trip but got %s", tC.expGuard, loopErr.Guard)
+			}
+			if loopErr.Blocks != tC.expBlocks {
+				t.Errorf("expected %d blocks to run but got %d", tC.expBlocks, loopErr.Blocks)
+			}
+			if loopErr.Flow != tC.flow {
+				t.Errorf("expected flow of '%s' but got '%s'", tC.flow, loopErr.Flow)
+			}
+			if len(loopErr.Path) != len(tC.expPath) {
+				t.Fatalf("expected loop path of %v but got %v", tC.expPath, loopErr.Path)
+			}
+			for i := range tC.expPath {
+				if loopErr.Path[i] != tC.expPath[i] {
+					t.Fatalf("expected loop path of %v but got %v", tC.expPath, loopErr.Path)
+				}
+			}
+		})
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		call := start(t, "Sample loop flow", &LoopGuards{})
+		for i := 0; i < 20; i++ {
+			<-call.Caller.O
+			call.Caller.I <- 'T'
+			<-call.Caller.O
+		}
+		<-call.Caller.O
+		call.Caller.I <- '1'
+		for range call.Caller.O {
+		}
+		if call.Err != nil {
+			t.Errorf("expected call to end without error but got %v", call.Err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package simulator_test
+
+import (
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+)
+
+// Calls in tests are made from testCustomerNumber to testDialledNumber, which starts the flow given to newTestSimulator.
+const (
+	testCustomerNumber = "+447878123456"
+	testDialledNumber  = "+441121234567"
+)
+
+// newTestSimulator creates a simulator with the given flows loaded, in which calls to testDialledNumber start in the named flow.
+func newTestSimulator(t *testing.T, flowName string, flows ...string) *Simulator {
+	t.Helper()
+	sim := New()
+	loadTestFlows(t, &sim, flowName, flows...)
+	return &sim
+}
+
+// loadTestFlows loads the given flows into a simulator and makes calls to testDialledNumber start in the named flow.
+func loadTestFlows(t *testing.T, sim *Simulator, flowName string, flows ...string) {
+	t.Helper()
+	for _, f := range flows {
+		if err := sim.LoadFlowJSON([]byte(f)); err != nil {
+			t.Fatalf("unexpected error parsing flow: %v", err)
+		}
+	}
+	if err := sim.SetStartingFlowFor(testDialledNumber, flowName); err != nil {
+		t.Fatalf("unexpected error setting starting flow: %v", err)
+	}
+}
+
+// startTestCall starts a call with the given config. Calls are from testCustomerNumber to testDialledNumber unless the config says otherwise.
+func startTestCall(t *testing.T, sim *Simulator, conf CallConfig) *Call {
+	t.Helper()
+	if conf.SourceNumber == "" {
+		conf.SourceNumber = testCustomerNumber
+	}
+	if conf.DestNumber == "" {
+		conf.DestNumber = testDialledNumber
+	}
+	call, err := sim.StartCall(conf)
+	if err != nil {
+		t.Fatalf("unexpected error starting call: %v", err)
+	}
+	return call
+}
@@ -0,0 +1,100 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HoursOfOperationOverride changes the opening hours for a range of dates, such as for a public holiday.
+type HoursOfOperationOverride struct {
+	Name string
+	// EffectiveFrom and EffectiveTill are the first and last dates (as YYYY-MM-DD) that the override applies to, in the time zone of the hours.
+	EffectiveFrom string
+	EffectiveTill string
+	// Config gives the opening hours while the override applies. Days with no config are closed.
+	Config []HoursOfOperationConfig
+}
+
+const overrideDateFormat = "2006-01-02"
+
+// IsOpen returns true if the hours of operation are open at the given time.
+// The time is converted to the time zone of the hours before the opening times are checked, so changes to and from daylight saving time are respected.
+// A range that ends at or before the time it starts runs overnight into the next day. A range from 00:00 to 00:00 is open all day.
+// If an override covers the date, its config is used for that date instead of the usual config.
+func (h HoursOfOperation) IsOpen(t time.Time) (bool, error) {
+	if h.Config == nil {
+		return false, fmt.Errorf("no opening hours loaded for hours of operation %s", h.Name)
+	}
+	loc, err := time.LoadLocation(h.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("invalid time zone for hours of operation %s: %v", h.Name, err)
+	}
+	t = t.In(loc)
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	// A range that started yesterday may still be running.
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		config, err := h.configFor(day)
+		if err != nil {
+			return false, err
+		}
+		for _, c := range config {
+			if !strings.EqualFold(c.Day, day.Weekday().String()) {
+				continue
+			}
+			start := c.StartTime.on(day)
+			end := c.EndTime.on(day)
+			if !end.After(start) {
+				end = c.EndTime.on(day.AddDate(0, 0, 1))
+			}
+			if !t.Before(start) && t.Before(end) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// configFor gives the opening hours that apply on the given date, taking overrides into account.
+func (h HoursOfOperation) configFor(day time.Time) ([]HoursOfOperationConfig, error) {
+	date := day.Format(overrideDateFormat)
+	for _, o := range h.Overrides {
+		if _, err := time.Parse(overrideDateFormat, o.EffectiveFrom); err != nil {
+			return nil, fmt.Errorf("invalid start date for override %s: %v", o.Name, err)
+		}
+		if _, err := time.Parse(overrideDateFormat, o.EffectiveTill); err != nil {
+			return nil, fmt.Errorf("invalid end date for override %s: %v", o.Name, err)
+		}
+		// Dates in this format sort in date order.
+		if date >= o.EffectiveFrom && date <= o.EffectiveTill {
+			return o.Config, nil
+		}
+	}
+	return h.Config, nil
+}
+
+// on gives this time of day on the given date, in the date's location.
+func (t HoursOfOperationTime) on(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hours, t.Minutes, 0, 0, day.Location())
+}
+
+// isInHours checks opening hours using the instance configuration.
+// name is the name of a queue if isQueue is true, otherwise the name of hours of operation.
+// known is false if the instance configuration says nothing about the queue or hours.
+func (m *instanceModel) isInHours(name string, isQueue bool, t time.Time) (open bool, known bool, err error) {
+	var h HoursOfOperation
+	var ok bool
+	if isQueue {
+		var q Queue
+		if q, ok = m.queueByName(name); !ok || q.HoursOfOperationID == "" {
+			return false, false, nil
+		}
+		if h, ok = m.hours[q.HoursOfOperationID]; !ok {
+			return false, true, fmt.Errorf("hours of operation %s for queue %s not loaded", q.HoursOfOperationID, name)
+		}
+	} else if h, ok = m.hoursByName(name); !ok {
+		return false, false, nil
+	}
+	open, err = h.IsOpen(t)
+	return open, true, err
+}
@@ -0,0 +1,251 @@
+package simulator_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+func TestHoursOfOperationIsOpen(t *testing.T) {
+	weekdays := []HoursOfOperationConfig{}
+	for _, d := range []string{"MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY"} {
+		weekdays = append(weekdays, HoursOfOperationConfig{Day: d, StartTime: HoursOfOperationTime{Hours: 9}, EndTime: HoursOfOperationTime{Hours: 17, Minutes: 30}})
+	}
+	office := HoursOfOperation{Name: "Office", TimeZone: "Europe/London", Config: weekdays}
+	overnight := HoursOfOperation{Name: "Overnight", TimeZone: "America/New_York", Config: []HoursOfOperationConfig{
+		{Day: "FRIDAY", StartTime: HoursOfOperationTime{Hours: 22}, EndTime: HoursOfOperationTime{Hours: 6}},
+		{Day: "SUNDAY", StartTime: HoursOfOperationTime{}, EndTime: HoursOfOperationTime{}},
+	}}
+	holidays := office
+	holidays.Overrides = []HoursOfOperationOverride{
+		{Name: "Christmas", EffectiveFrom: "2024-12-25", EffectiveTill: "2024-12-26"},
+		{Name: "Christmas Eve", EffectiveFrom: "2024-12-24", EffectiveTill: "2024-12-24", Config: []HoursOfOperationConfig{
+			{Day: "TUESDAY", StartTime: HoursOfOperationTime{Hours: 9}, EndTime: HoursOfOperationTime{Hours: 12}},
+		}},
+	}
+	utc := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("bad test time: %v", err)
+		}
+		return tm
+	}
+	testCases := []struct {
+		desc   string
+		hours  HoursOfOperation
+		time   time.Time
+		exp    bool
+		expErr string
+	}{
+		{
+			desc:  "weekday - open",
+			hours: office,
+			time:  utc("2024-01-15T12:00:00Z"),
+			exp:   true,
+		},
+		{
+			desc:  "weekday - before opening",
+			hours: office,
+			time:  utc("2024-01-15T08:59:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "weekday - at closing",
+			hours: office,
+			time:  utc("2024-01-15T17:30:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "weekend - closed",
+			hours: office,
+			time:  utc("2024-01-13T12:00:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "summer time - open an hour earlier in UTC",
+			hours: office,
+			time:  utc("2024-04-01T08:30:00Z"),
+			exp:   true,
+		},
+		{
+			desc:  "summer time - closed an hour earlier in UTC",
+			hours: office,
+			time:  utc("2024-04-01T16:45:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "day after clocks go back",
+			hours: office,
+			time:  utc("2024-10-28T08:30:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "overnight - late evening",
+			hours: overnight,
+			time:  utc("2024-01-20T03:30:00Z"),
+			exp:   true,
+		},
+		{
+			desc:  "overnight - early next morning",
+			hours: overnight,
+			time:  utc("2024-01-20T10:59:00Z"),
+			exp:   true,
+		},
+		{
+			desc:  "overnight - after closing",
+			hours: overnight,
+			time:  utc("2024-01-20T11:00:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "all day",
+			hours: overnight,
+			time:  utc("2024-01-22T04:59:00Z"),
+			exp:   true,
+		},
+		{
+			desc:  "holiday - closed",
+			hours: holidays,
+			time:  utc("2024-12-26T12:00:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "holiday - shortened hours",
+			hours: holidays,
+			time:  utc("2024-12-24T11:00:00Z"),
+			exp:   true,
+		},
+		{
+			desc:  "holiday - after shortened hours",
+			hours: holidays,
+			time:  utc("2024-12-24T13:00:00Z"),
+			exp:   false,
+		},
+		{
+			desc:  "after holiday",
+			hours: holidays,
+			time:  utc("2024-12-27T12:00:00Z"),
+			exp:   true,
+		},
+		{
+			desc:   "bad time zone",
+			hours:  HoursOfOperation{Name: "Mars", TimeZone: "Mars/Olympus_Mons", Config: weekdays},
+			time:   utc("2024-01-15T12:00:00Z"),
+			expErr: "invalid time zone for hours of operation Mars: unknown time zone Mars/Olympus_Mons",
+		},
+		{
+			desc:   "no config",
+			hours:  HoursOfOperation{Name: "Summary"},
+			time:   utc("2024-01-15T12:00:00Z"),
+			expErr: "no opening hours loaded for hours of operation Summary",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			open, err := tC.hours.IsOpen(tC.time)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			if open != tC.exp {
+				t.Errorf("expected open to be %v but it was %v", tC.exp, open)
+			}
+		})
+	}
+}
+
+var sampleQueueHours = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0008-000000000001","type":"SetQueue","branches":[{"condition":"Success","transition":"00000000-0000-4000-0008-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0008-000000000005"}],"parameters":[{"name":"Queue","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001","namespace":null,"resourceName":"Sales"}]},
+        {"id":"00000000-0000-4000-0008-000000000002","type":"CheckHoursOfOperation","branches":[{"condition":"True","transition":"00000000-0000-4000-0008-000000000003"},{"condition":"False","transition":"00000000-0000-4000-0008-000000000004"},{"condition":"Error","transition":"00000000-0000-4000-0008-000000000005"}],"parameters":[]},
+        {"id":"00000000-0000-4000-0008-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0008-000000000006"}],"parameters":[{"name":"Text","value":"We are open.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0008-000000000004","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0008-000000000006"}],"parameters":[{"name":"Text","value":"We are closed.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0008-000000000005","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0008-000000000006"}],"parameters":[{"name":"Text","value":"Something went wrong.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0008-000000000006","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0008-000000000001",
+    "metadata":{"name":"Sample queue hours flow","description":"","type":"contactFlow"}
+}`
+
+var sampleListHoursOverrides = `{
+    "HoursOfOperationOverrideList": [
+        {
+            "HoursOfOperationOverrideId": "cccccccc-0000-4000-0000-cccccccc0001",
+            "HoursOfOperationId": "dddddddd-0000-4000-0000-dddddddd0001",
+            "HoursOfOperationArn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/operating-hours/dddddddd-0000-4000-0000-dddddddd0001",
+            "Name": "Bank holiday",
+            "Config": [],
+            "EffectiveFrom": "2024-05-06",
+            "EffectiveTill": "2024-05-06"
+        }
+    ]
+}`
+
+func TestQueueHoursOfOperation(t *testing.T) {
+	sim := newTestSimulator(t, "Sample queue hours flow", sampleQueueHours)
+	for _, j := range []string{sampleDescribeQueue, sampleDescribeHours, sampleListHoursOverrides} {
+		if err := sim.LoadInstanceJSON([]byte(j)); err != nil {
+			t.Fatalf("unexpected error loading instance: %v", err)
+		}
+	}
+	testCases := []struct {
+		desc string
+		time string
+		exp  string
+	}{
+		{
+			desc: "open",
+			time: "2024-04-29T16:00:00Z",
+			exp:  "We are open.",
+		},
+		{
+			desc: "closed",
+			time: "2024-04-29T17:00:00Z",
+			exp:  "We are closed.",
+		},
+		{
+			desc: "bank holiday",
+			time: "2024-05-06T12:00:00Z",
+			exp:  "We are closed.",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tm, _ := time.Parse(time.RFC3339, tC.time)
+			call := startTestCall(t, sim, CallConfig{SourceNumber: "+447878000001", Time: tm})
+			expect := flowtest.New(t, call)
+			expect.Prompt().ToEqual(tC.exp)
+		})
+	}
+}
+
+var sampleQueueHoursAfterWait = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0020-000000000001","type":"GetUserInput","branches":[{"condition":"Timeout","transition":"00000000-0000-4000-0020-000000000002"},{"condition":"NoMatch","transition":"00000000-0000-4000-0020-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0020-000000000002"}],"parameters":[{"name":"Text","value":"Please hold."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"120"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0020-000000000002","type":"Transfer","branches":[],"parameters":[{"name":"ContactFlowId","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/00000000-0000-4000-0008-000000000000","resourceName":"Sample queue hours flow"}],"target":"Flow"}
+    ],
+    "start":"00000000-0000-4000-0020-000000000001",
+    "metadata":{"name":"Sample queue hours after wait flow","description":"","type":"contactFlow"}
+}`
+
+func TestQueueHoursOfOperationAfterWait(t *testing.T) {
+	sim := newTestSimulator(t, "Sample queue hours after wait flow", sampleQueueHours, sampleQueueHoursAfterWait)
+	for _, j := range []string{sampleDescribeQueue, sampleDescribeHours, sampleListHoursOverrides} {
+		if err := sim.LoadInstanceJSON([]byte(j)); err != nil {
+			t.Fatalf("unexpected error loading instance: %v", err)
+		}
+	}
+	// The call starts a minute before closing, then waits two minutes before the hours are checked.
+	tm, _ := time.Parse(time.RFC3339, "2024-04-29T16:29:00Z")
+	call := startTestCall(t, sim, CallConfig{SourceNumber: "+447878000001", Time: tm})
+	<-call.Caller.O
+	call.Caller.I <- 'T'
+	if p := <-call.Caller.O; p != "We are closed." {
+		t.Errorf("expected queue to be closed after waiting but got '%s'", p)
+	}
+}
@@ -0,0 +1,364 @@
+package simulator
+
+import (
+	"strings"
+)
+
+// Instance describes the configuration of an Amazon Connect instance that flows refer to.
+// It can be built by hand, or loaded from the output of the AWS CLI with Simulator.LoadInstanceJSON.
+type Instance struct {
+	Queues           []Queue
+	HoursOfOperation []HoursOfOperation
+	Prompts          []Prompt
+	PhoneNumbers     []PhoneNumber
+	RoutingProfiles  []RoutingProfile
+	Users            []User
+	Flows            []ContactFlow
+}
+
+// Queue describes a queue in the simulated Amazon Connect instance.
+type Queue struct {
+	ID                       string
+	ARN                      string
+	Name                     string
+	HoursOfOperationID       string
+	OutboundCallerIDName     string
+	OutboundCallerIDNumberID string
+	OutboundFlowID           string
+	// MaxContacts is the most contacts that may wait in the queue. Zero means no limit.
+	MaxContacts int
+}
+
+// HoursOfOperation describes the opening hours that can be attached to a queue or checked directly by a flow.
+type HoursOfOperation struct {
+	ID       string
+	ARN      string
+	Name     string
+	TimeZone string
+	Config   []HoursOfOperationConfig
+	// Overrides replace the usual opening hours on particular dates.
+	Overrides []HoursOfOperationOverride
+}
+
+// HoursOfOperationConfig is a single range of opening hours on one day of the week.
+type HoursOfOperationConfig struct {
+	// Day is the upper case English name of the day (e.g. MONDAY).
+	Day       string
+	StartTime HoursOfOperationTime
+	EndTime   HoursOfOperationTime
+}
+
+// HoursOfOperationTime is a time of day used in opening hours.
+type HoursOfOperationTime struct {
+	Hours   int
+	Minutes int
+}
+
+// Prompt describes a recorded prompt stored in the instance.
+type Prompt struct {
+	ID   string
+	ARN  string
+	Name string
+}
+
+// PhoneNumber describes a phone number claimed by the instance.
+type PhoneNumber struct {
+	ID          string
+	ARN         string
+	Number      string
+	Type        string
+	CountryCode string
+}
+
+// RoutingProfile describes a routing profile that agents are assigned to.
+type RoutingProfile struct {
+	ID                     string
+	ARN                    string
+	Name                   string
+	DefaultOutboundQueueID string
+}
+
+// User describes an agent or other user of the instance.
+type User struct {
+	ID               string
+	ARN              string
+	Username         string
+	FirstName        string
+	LastName         string
+	RoutingProfileID string
+}
+
+// ContactFlow describes a flow published in the instance.
+type ContactFlow struct {
+	ID   string
+	ARN  string
+	Name string
+	Type string
+}
+
+// instanceModel holds the instance configuration loaded into a simulator, indexed by resource ID.
+type instanceModel struct {
+	arn             string
+	queues          map[string]Queue
+	hours           map[string]HoursOfOperation
+	prompts         map[string]Prompt
+	phoneNumbers    map[string]PhoneNumber
+	routingProfiles map[string]RoutingProfile
+	users           map[string]User
+	flows           map[string]ContactFlow
+}
+
+func newInstanceModel() *instanceModel {
+	return &instanceModel{
+		queues:          map[string]Queue{},
+		hours:           map[string]HoursOfOperation{},
+		prompts:         map[string]Prompt{},
+		phoneNumbers:    map[string]PhoneNumber{},
+		routingProfiles: map[string]RoutingProfile{},
+		users:           map[string]User{},
+		flows:           map[string]ContactFlow{},
+	}
+}
+
+// AddQueue adds a queue to the simulator's catalogue of queues.
+// The catalogue is used to find the name of a queue when a flow only knows its ARN,
+// such as when a Set Working Queue block takes the queue from a contact attribute.
+func (cs *Simulator) AddQueue(q Queue) {
+	cs.LoadInstance(Instance{Queues: []Queue{q}})
+}
+
+// LoadInstance adds configuration of the Connect instance to the simulator.
+// Resources are matched by ID (or by ARN if no ID is given). Details of a resource already loaded are filled in, rather than replaced,
+// so summaries from list commands and details from describe commands can be loaded in any order.
+func (cs *Simulator) LoadInstance(i Instance) {
+	m := cs.instance
+	for _, q := range i.Queues {
+		key := resourceKey(q.ID, q.ARN)
+		m.queues[key] = m.queues[key].merge(q)
+		m.setARN(q.ARN)
+	}
+	for _, h := range i.HoursOfOperation {
+		key := resourceKey(h.ID, h.ARN)
+		m.hours[key] = m.hours[key].merge(h)
+		m.setARN(h.ARN)
+	}
+	for _, p := range i.Prompts {
+		key := resourceKey(p.ID, p.ARN)
+		m.prompts[key] = m.prompts[key].merge(p)
+		m.setARN(p.ARN)
+	}
+	for _, n := range i.PhoneNumbers {
+		key := resourceKey(n.ID, n.ARN)
+		m.phoneNumbers[key] = m.phoneNumbers[key].merge(n)
+	}
+	for _, r := range i.RoutingProfiles {
+		key := resourceKey(r.ID, r.ARN)
+		m.routingProfiles[key] = m.routingProfiles[key].merge(r)
+		m.setARN(r.ARN)
+	}
+	for _, u := range i.Users {
+		key := resourceKey(u.ID, u.ARN)
+		m.users[key] = m.users[key].merge(u)
+		m.setARN(u.ARN)
+	}
+	for _, f := range i.Flows {
+		key := resourceKey(f.ID, f.ARN)
+		m.flows[key] = m.flows[key].merge(f)
+		m.setARN(f.ARN)
+	}
+}
+
+// Instance returns the configuration of the Connect instance loaded into the simulator.
+func (cs *Simulator) Instance() Instance {
+	m := cs.instance
+	i := Instance{}
+	for _, q := range m.queues {
+		i.Queues = append(i.Queues, q)
+	}
+	for _, h := range m.hours {
+		i.HoursOfOperation = append(i.HoursOfOperation, h)
+	}
+	for _, p := range m.prompts {
+		i.Prompts = append(i.Prompts, p)
+	}
+	for _, n := range m.phoneNumbers {
+		i.PhoneNumbers = append(i.PhoneNumbers, n)
+	}
+	for _, r := range m.routingProfiles {
+		i.RoutingProfiles = append(i.RoutingProfiles, r)
+	}
+	for _, u := range m.users {
+		i.Users = append(i.Users, u)
+	}
+	for _, f := range m.flows {
+		i.Flows = append(i.Flows, f)
+	}
+	return i
+}
+
+// resourceKey gives the key a resource is stored under: its ID, or the last part of its ARN.
+func resourceKey(id string, arn string) string {
+	if id != "" {
+		return id
+	}
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// setARN records the ARN of the instance, taken from the ARN of a resource within it.
+func (m *instanceModel) setARN(resourceARN string) {
+	if m.arn != "" {
+		return
+	}
+	i := strings.Index(resourceARN, ":instance/")
+	if i < 0 {
+		return
+	}
+	end := strings.Index(resourceARN[i+len(":instance/"):], "/")
+	if end < 0 {
+		m.arn = resourceARN
+		return
+	}
+	m.arn = resourceARN[:i+len(":instance/")+end]
+}
+
+// queueByARN finds a queue by its ARN.
+// An agent's personal queue is found from the user with the same ID.
+func (m *instanceModel) queueByARN(arn string) (Queue, bool) {
+	for _, q := range m.queues {
+		if q.ARN == arn {
+			return q, true
+		}
+	}
+	if i := strings.Index(arn, "/queue/agent/"); i >= 0 {
+		if u, ok := m.users[arn[i+len("/queue/agent/"):]]; ok {
+			return Queue{ID: u.ID, ARN: arn, Name: u.Username}, true
+		}
+	}
+	return Queue{}, false
+}
+
+// queueByName finds a queue by its name.
+func (m *instanceModel) queueByName(name string) (Queue, bool) {
+	for _, q := range m.queues {
+		if q.Name == name {
+			return q, true
+		}
+	}
+	return Queue{}, false
+}
+
+// hoursByARN finds hours of operation by ARN.
+func (m *instanceModel) hoursByARN(arn string) (HoursOfOperation, bool) {
+	for _, h := range m.hours {
+		if h.ARN == arn {
+			return h, true
+		}
+	}
+	return HoursOfOperation{}, false
+}
+
+// hoursByName finds hours of operation by name.
+func (m *instanceModel) hoursByName(name string) (HoursOfOperation, bool) {
+	for _, h := range m.hours {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return HoursOfOperation{}, false
+}
+
+// flowByARN finds a flow by ARN.
+func (m *instanceModel) flowByARN(arn string) (ContactFlow, bool) {
+	for _, f := range m.flows {
+		if f.ARN == arn {
+			return f, true
+		}
+	}
+	return ContactFlow{}, false
+}
+
+// outboundNumber gives the phone number used as caller ID for calls made from the queue.
+func (m *instanceModel) outboundNumber(q Queue) (string, bool) {
+	n, ok := m.phoneNumbers[q.OutboundCallerIDNumberID]
+	if !ok || n.Number == "" {
+		return "", false
+	}
+	return n.Number, true
+}
+
+func or(a string, b string) string {
+	if b != "" {
+		return b
+	}
+	return a
+}
+
+func (q Queue) merge(o Queue) Queue {
+	q.ID = or(q.ID, o.ID)
+	q.ARN = or(q.ARN, o.ARN)
+	q.Name = or(q.Name, o.Name)
+	q.HoursOfOperationID = or(q.HoursOfOperationID, o.HoursOfOperationID)
+	q.OutboundCallerIDName = or(q.OutboundCallerIDName, o.OutboundCallerIDName)
+	q.OutboundCallerIDNumberID = or(q.OutboundCallerIDNumberID, o.OutboundCallerIDNumberID)
+	q.OutboundFlowID = or(q.OutboundFlowID, o.OutboundFlowID)
+	if o.MaxContacts != 0 {
+		q.MaxContacts = o.MaxContacts
+	}
+	return q
+}
+
+func (h HoursOfOperation) merge(o HoursOfOperation) HoursOfOperation {
+	h.ID = or(h.ID, o.ID)
+	h.ARN = or(h.ARN, o.ARN)
+	h.Name = or(h.Name, o.Name)
+	h.TimeZone = or(h.TimeZone, o.TimeZone)
+	if o.Config != nil {
+		h.Config = o.Config
+	}
+	if o.Overrides != nil {
+		h.Overrides = o.Overrides
+	}
+	return h
+}
+
+func (p Prompt) merge(o Prompt) Prompt {
+	p.ID = or(p.ID, o.ID)
+	p.ARN = or(p.ARN, o.ARN)
+	p.Name = or(p.Name, o.Name)
+	return p
+}
+
+func (n PhoneNumber) merge(o PhoneNumber) PhoneNumber {
+	n.ID = or(n.ID, o.ID)
+	n.ARN = or(n.ARN, o.ARN)
+	n.Number = or(n.Number, o.Number)
+	n.Type = or(n.Type, o.Type)
+	n.CountryCode = or(n.CountryCode, o.CountryCode)
+	return n
+}
+
+func (r RoutingProfile) merge(o RoutingProfile) RoutingProfile {
+	r.ID = or(r.ID, o.ID)
+	r.ARN = or(r.ARN, o.ARN)
+	r.Name = or(r.Name, o.Name)
+	r.DefaultOutboundQueueID = or(r.DefaultOutboundQueueID, o.DefaultOutboundQueueID)
+	return r
+}
+
+func (u User) merge(o User) User {
+	u.ID = or(u.ID, o.ID)
+	u.ARN = or(u.ARN, o.ARN)
+	u.Username = or(u.Username, o.Username)
+	u.FirstName = or(u.FirstName, o.FirstName)
+	u.LastName = or(u.LastName, o.LastName)
+	u.RoutingProfileID = or(u.RoutingProfileID, o.RoutingProfileID)
+	return u
+}
+
+func (f ContactFlow) merge(o ContactFlow) ContactFlow {
+	f.ID = or(f.ID, o.ID)
+	f.ARN = or(f.ARN, o.ARN)
+	f.Name = or(f.Name, o.Name)
+	f.Type = or(f.Type, o.Type)
+	return f
+}
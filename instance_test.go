@@ -0,0 +1,168 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleListQueues = `{
+    "QueueSummaryList": [
+        {"Id": "ffffffff-0000-4000-0000-ffffffff0001", "Arn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001", "Name": "Sales", "QueueType": "STANDARD"},
+        {"Id": "ffffffff-0000-4000-0000-ffffffff0002", "Arn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0002", "Name": "Billing", "QueueType": "STANDARD"}
+    ]
+}`
+
+var sampleDescribeQueue = `{
+    "Queue": {
+        "Name": "Sales",
+        "QueueArn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
+        "QueueId": "ffffffff-0000-4000-0000-ffffffff0001",
+        "OutboundCallerConfig": {
+            "OutboundCallerIdName": "Example Sales",
+            "OutboundCallerIdNumberId": "eeeeeeee-0000-4000-0000-eeeeeeee0001"
+        },
+        "HoursOfOperationId": "dddddddd-0000-4000-0000-dddddddd0001",
+        "MaxContacts": 1,
+        "Status": "ENABLED",
+        "Tags": {}
+    }
+}`
+
+var sampleListPhoneNumbers = `{
+    "PhoneNumberSummaryList": [
+        {"Id": "eeeeeeee-0000-4000-0000-eeeeeeee0001", "Arn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/phone-number/eeeeeeee-0000-4000-0000-eeeeeeee0001", "PhoneNumber": "+441121234567", "PhoneNumberType": "DID", "PhoneNumberCountryCode": "GB"}
+    ]
+}`
+
+var sampleDescribeHours = `{
+    "HoursOfOperation": {
+        "HoursOfOperationId": "dddddddd-0000-4000-0000-dddddddd0001",
+        "HoursOfOperationArn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/operating-hours/dddddddd-0000-4000-0000-dddddddd0001",
+        "Name": "Office Hours",
+        "TimeZone": "Europe/London",
+        "Config": [
+            {"Day": "MONDAY", "StartTime": {"Hours": 9, "Minutes": 0}, "EndTime": {"Hours": 17, "Minutes": 30}}
+        ]
+    }
+}`
+
+var sampleListUsers = `{
+    "UserSummaryList": [
+        {"Id": "aaaaaaaa-0000-4000-0000-000000000001", "Arn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/agent/aaaaaaaa-0000-4000-0000-000000000001", "Username": "jsmith"}
+    ]
+}`
+
+var sampleInstanceQueue = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0007-000000000001","type":"SetQueue","branches":[{"condition":"Success","transition":"00000000-0000-4000-0007-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0007-000000000004"}],"parameters":[{"name":"Queue","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001","namespace":null,"resourceName":"Sales"}]},
+        {"id":"00000000-0000-4000-0007-000000000002","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0007-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0007-000000000004"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:queue-check"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0007-000000000003","type":"Transfer","branches":[{"condition":"AtCapacity","transition":"00000000-0000-4000-0007-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-0007-000000000004"}],"parameters":[],"target":"Queue"},
+        {"id":"00000000-0000-4000-0007-000000000004","type":"Disconnect","branches":[],"parameters":[]},
+        {"id":"00000000-0000-4000-0007-000000000005","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0007-000000000004"}],"parameters":[{"name":"Text","value":"All of our agents are busy.","namespace":null},{"name":"TextToSpeechType","value":"text"}]}
+    ],
+    "start":"00000000-0000-4000-0007-000000000001",
+    "metadata":{"name":"Sample instance queue flow","description":"","type":"contactFlow"}
+}`
+
+func TestLoadInstanceJSON(t *testing.T) {
+	sim := New()
+	for _, j := range []string{sampleDescribeQueue, sampleListQueues, sampleListPhoneNumbers, sampleDescribeHours, sampleListUsers} {
+		if err := sim.LoadInstanceJSON([]byte(j)); err != nil {
+			t.Fatalf("unexpected error loading instance: %v", err)
+		}
+	}
+	if err := sim.LoadInstanceJSON([]byte(`{"Unknown":[]}`)); err == nil {
+		t.Error("expected an error loading JSON with no resources but got none")
+	}
+	if err := sim.LoadInstanceJSON([]byte(`<xml />`)); err == nil {
+		t.Error("expected an error loading invalid JSON but got none")
+	}
+
+	i := sim.Instance()
+	if len(i.Queues) != 2 {
+		t.Fatalf("expected 2 queues but got %d", len(i.Queues))
+	}
+	var sales Queue
+	for _, q := range i.Queues {
+		if q.Name == "Sales" {
+			sales = q
+		}
+	}
+	exp := Queue{
+		ID:                       "ffffffff-0000-4000-0000-ffffffff0001",
+		ARN:                      "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
+		Name:                     "Sales",
+		HoursOfOperationID:       "dddddddd-0000-4000-0000-dddddddd0001",
+		OutboundCallerIDName:     "Example Sales",
+		OutboundCallerIDNumberID: "eeeeeeee-0000-4000-0000-eeeeeeee0001",
+		MaxContacts:              1,
+	}
+	if sales != exp {
+		t.Errorf("expected merged queue of %+v but got %+v", exp, sales)
+	}
+	if len(i.PhoneNumbers) != 1 || i.PhoneNumbers[0].Number != "+441121234567" {
+		t.Errorf("expected phone number +441121234567 but got %+v", i.PhoneNumbers)
+	}
+	if len(i.HoursOfOperation) != 1 || i.HoursOfOperation[0].TimeZone != "Europe/London" || len(i.HoursOfOperation[0].Config) != 1 {
+		t.Errorf("expected Office Hours in Europe/London but got %+v", i.HoursOfOperation)
+	}
+	if len(i.Users) != 1 || i.Users[0].Username != "jsmith" {
+		t.Errorf("expected user jsmith but got %+v", i.Users)
+	}
+}
+
+func TestInstanceQueue(t *testing.T) {
+	sim := newTestSimulator(t, "Sample instance queue flow", sampleInstanceQueue)
+	for _, j := range []string{sampleDescribeQueue, sampleListPhoneNumbers} {
+		if err := sim.LoadInstanceJSON([]byte(j)); err != nil {
+			t.Fatalf("unexpected error loading instance: %v", err)
+		}
+	}
+	type payload struct {
+		Details struct {
+			ContactData struct {
+				InstanceARN string
+				Queue       struct {
+					Name             string
+					ARN              string
+					OutboundCallerID struct {
+						Address string
+					} `json:"OutboundCallerId"`
+				}
+			}
+		}
+	}
+	err := sim.RegisterLambda("queue-check", func(ctx context.Context, in payload) (map[string]string, error) {
+		cd := in.Details.ContactData
+		if cd.InstanceARN != "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff" {
+			t.Errorf("expected instance ARN in payload but got '%s'", cd.InstanceARN)
+		}
+		if cd.Queue.Name != "Sales" {
+			t.Errorf("expected queue name of Sales but got '%s'", cd.Queue.Name)
+		}
+		if cd.Queue.OutboundCallerID.Address != "+441121234567" {
+			t.Errorf("expected outbound caller ID of +441121234567 but got '%s'", cd.Queue.OutboundCallerID.Address)
+		}
+		return map[string]string{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+
+	t.Run("queue has room", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{SourceNumber: "+447878000001"})
+		expect := flowtest.New(t, call)
+		expect.Transfer().ToQueue("Sales")
+	})
+	t.Run("queue at capacity", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{SourceNumber: "+447878000002"})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("All of our agents are busy.")
+	})
+	if n := len(sim.QueuedContacts("Sales")); n != 1 {
+		t.Errorf("expected 1 contact in queue but got %d", n)
+	}
+}
@@ -0,0 +1,214 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// cliOutput covers the shapes of JSON printed by the aws connect describe-* and list-* commands.
+type cliOutput struct {
+	Queue                     *cliQueue            `json:"Queue"`
+	QueueSummaryList          []cliSummary         `json:"QueueSummaryList"`
+	HoursOfOperation          *cliHoursOfOperation `json:"HoursOfOperation"`
+	HoursOfOperationSummaries []cliSummary         `json:"HoursOfOperationSummaryList"`
+	HoursOfOperationOverrides []cliHoursOverride   `json:"HoursOfOperationOverrideList"`
+	Prompt                    *cliPrompt           `json:"Prompt"`
+	PromptSummaryList         []cliSummary         `json:"PromptSummaryList"`
+	ClaimedPhoneNumber        *cliPhoneNumber      `json:"ClaimedPhoneNumberSummary"`
+	PhoneNumberSummaryList    []cliPhoneNumber     `json:"PhoneNumberSummaryList"`
+	PhoneNumberSummaryListV2  []cliPhoneNumber     `json:"ListPhoneNumbersSummaryList"`
+	RoutingProfile            *cliRoutingProfile   `json:"RoutingProfile"`
+	RoutingProfileSummaries   []cliSummary         `json:"RoutingProfileSummaryList"`
+	User                      *cliUser             `json:"User"`
+	UserSummaryList           []cliSummary         `json:"UserSummaryList"`
+	ContactFlow               *cliSummary          `json:"ContactFlow"`
+	ContactFlowSummaryList    []cliSummary         `json:"ContactFlowSummaryList"`
+}
+
+type cliSummary struct {
+	ID              string `json:"Id"`
+	ARN             string `json:"Arn"`
+	Name            string `json:"Name"`
+	Username        string `json:"Username"`
+	Type            string `json:"Type"`
+	ContactFlowType string `json:"ContactFlowType"`
+}
+
+type cliQueue struct {
+	QueueID              string `json:"QueueId"`
+	QueueARN             string `json:"QueueArn"`
+	Name                 string `json:"Name"`
+	HoursOfOperationID   string `json:"HoursOfOperationId"`
+	MaxContacts          int    `json:"MaxContacts"`
+	OutboundCallerConfig struct {
+		OutboundCallerIDName     string `json:"OutboundCallerIdName"`
+		OutboundCallerIDNumberID string `json:"OutboundCallerIdNumberId"`
+		OutboundFlowID           string `json:"OutboundFlowId"`
+	} `json:"OutboundCallerConfig"`
+}
+
+type cliHoursOfOperation struct {
+	HoursOfOperationID  string                   `json:"HoursOfOperationId"`
+	HoursOfOperationARN string                   `json:"HoursOfOperationArn"`
+	Name                string                   `json:"Name"`
+	TimeZone            string                   `json:"TimeZone"`
+	Config              []HoursOfOperationConfig `json:"Config"`
+}
+
+type cliHoursOverride struct {
+	HoursOfOperationID  string                   `json:"HoursOfOperationId"`
+	HoursOfOperationARN string                   `json:"HoursOfOperationArn"`
+	Name                string                   `json:"Name"`
+	EffectiveFrom       string                   `json:"EffectiveFrom"`
+	EffectiveTill       string                   `json:"EffectiveTill"`
+	Config              []HoursOfOperationConfig `json:"Config"`
+}
+
+type cliPrompt struct {
+	PromptID  string `json:"PromptId"`
+	PromptARN string `json:"PromptARN"`
+	Name      string `json:"Name"`
+}
+
+type cliPhoneNumber struct {
+	ID                     string `json:"Id"`
+	ARN                    string `json:"Arn"`
+	PhoneNumberID          string `json:"PhoneNumberId"`
+	PhoneNumberARN         string `json:"PhoneNumberArn"`
+	PhoneNumber            string `json:"PhoneNumber"`
+	PhoneNumberType        string `json:"PhoneNumberType"`
+	PhoneNumberCountryCode string `json:"PhoneNumberCountryCode"`
+}
+
+type cliRoutingProfile struct {
+	RoutingProfileID       string `json:"RoutingProfileId"`
+	RoutingProfileARN      string `json:"RoutingProfileArn"`
+	Name                   string `json:"Name"`
+	DefaultOutboundQueueID string `json:"DefaultOutboundQueueId"`
+}
+
+type cliUser struct {
+	ID               string `json:"Id"`
+	ARN              string `json:"Arn"`
+	Username         string `json:"Username"`
+	RoutingProfileID string `json:"RoutingProfileId"`
+	IdentityInfo     struct {
+		FirstName string `json:"FirstName"`
+		LastName  string `json:"LastName"`
+	} `json:"IdentityInfo"`
+}
+
+// LoadInstanceJSON takes the JSON output of an aws connect describe-* or list-* command and adds the resources it describes to the simulator.
+// Queues, hours of operation (and their overrides), prompts, phone numbers, routing profiles, users and contact flows are understood.
+// Call it once for the output of each command. A describe command adds detail to a resource already loaded from a list command, and vice versa.
+func (cs *Simulator) LoadInstanceJSON(bytes []byte) error {
+	o := cliOutput{}
+	err := json.Unmarshal(bytes, &o)
+	if err != nil {
+		return err
+	}
+	i := o.instance()
+	if len(i.Queues)+len(i.HoursOfOperation)+len(i.Prompts)+len(i.PhoneNumbers)+len(i.RoutingProfiles)+len(i.Users)+len(i.Flows) == 0 {
+		return errors.New("no Connect instance resources found in JSON")
+	}
+	cs.LoadInstance(i)
+	return nil
+}
+
+func (o cliOutput) instance() Instance {
+	i := Instance{}
+	if q := o.Queue; q != nil {
+		i.Queues = append(i.Queues, Queue{
+			ID:                       q.QueueID,
+			ARN:                      q.QueueARN,
+			Name:                     q.Name,
+			HoursOfOperationID:       q.HoursOfOperationID,
+			OutboundCallerIDName:     q.OutboundCallerConfig.OutboundCallerIDName,
+			OutboundCallerIDNumberID: q.OutboundCallerConfig.OutboundCallerIDNumberID,
+			OutboundFlowID:           q.OutboundCallerConfig.OutboundFlowID,
+			MaxContacts:              q.MaxContacts,
+		})
+	}
+	for _, s := range o.QueueSummaryList {
+		i.Queues = append(i.Queues, Queue{ID: s.ID, ARN: s.ARN, Name: s.Name})
+	}
+	if h := o.HoursOfOperation; h != nil {
+		i.HoursOfOperation = append(i.HoursOfOperation, HoursOfOperation{
+			ID:       h.HoursOfOperationID,
+			ARN:      h.HoursOfOperationARN,
+			Name:     h.Name,
+			TimeZone: h.TimeZone,
+			Config:   h.Config,
+		})
+	}
+	for _, s := range o.HoursOfOperationSummaries {
+		i.HoursOfOperation = append(i.HoursOfOperation, HoursOfOperation{ID: s.ID, ARN: s.ARN, Name: s.Name})
+	}
+	// Overrides are listed individually, so are gathered up under the hours they belong to.
+	overridden := map[string]int{}
+	for _, ov := range o.HoursOfOperationOverrides {
+		j, ok := overridden[ov.HoursOfOperationID]
+		if !ok {
+			j = len(i.HoursOfOperation)
+			overridden[ov.HoursOfOperationID] = j
+			i.HoursOfOperation = append(i.HoursOfOperation, HoursOfOperation{ID: ov.HoursOfOperationID, ARN: ov.HoursOfOperationARN, Overrides: []HoursOfOperationOverride{}})
+		}
+		i.HoursOfOperation[j].Overrides = append(i.HoursOfOperation[j].Overrides, HoursOfOperationOverride{
+			Name:          ov.Name,
+			EffectiveFrom: ov.EffectiveFrom,
+			EffectiveTill: ov.EffectiveTill,
+			Config:        ov.Config,
+		})
+	}
+	if p := o.Prompt; p != nil {
+		i.Prompts = append(i.Prompts, Prompt{ID: p.PromptID, ARN: p.PromptARN, Name: p.Name})
+	}
+	for _, s := range o.PromptSummaryList {
+		i.Prompts = append(i.Prompts, Prompt{ID: s.ID, ARN: s.ARN, Name: s.Name})
+	}
+	numbers := append(o.PhoneNumberSummaryList, o.PhoneNumberSummaryListV2...)
+	if o.ClaimedPhoneNumber != nil {
+		numbers = append(numbers, *o.ClaimedPhoneNumber)
+	}
+	for _, n := range numbers {
+		i.PhoneNumbers = append(i.PhoneNumbers, PhoneNumber{
+			ID:          or(n.ID, n.PhoneNumberID),
+			ARN:         or(n.ARN, n.PhoneNumberARN),
+			Number:      n.PhoneNumber,
+			Type:        n.PhoneNumberType,
+			CountryCode: n.PhoneNumberCountryCode,
+		})
+	}
+	if r := o.RoutingProfile; r != nil {
+		i.RoutingProfiles = append(i.RoutingProfiles, RoutingProfile{
+			ID:                     r.RoutingProfileID,
+			ARN:                    r.RoutingProfileARN,
+			Name:                   r.Name,
+			DefaultOutboundQueueID: r.DefaultOutboundQueueID,
+		})
+	}
+	for _, s := range o.RoutingProfileSummaries {
+		i.RoutingProfiles = append(i.RoutingProfiles, RoutingProfile{ID: s.ID, ARN: s.ARN, Name: s.Name})
+	}
+	if u := o.User; u != nil {
+		i.Users = append(i.Users, User{
+			ID:               u.ID,
+			ARN:              u.ARN,
+			Username:         u.Username,
+			FirstName:        u.IdentityInfo.FirstName,
+			LastName:         u.IdentityInfo.LastName,
+			RoutingProfileID: u.RoutingProfileID,
+		})
+	}
+	for _, s := range o.UserSummaryList {
+		i.Users = append(i.Users, User{ID: s.ID, ARN: s.ARN, Username: s.Username})
+	}
+	flows := o.ContactFlowSummaryList
+	if o.ContactFlow != nil {
+		flows = append(flows, *o.ContactFlow)
+	}
+	for _, s := range flows {
+		i.Flows = append(i.Flows, ContactFlow{ID: s.ID, ARN: s.ARN, Name: s.Name, Type: or(s.ContactFlowType, s.Type)})
+	}
+	return i
+}
@@ -29,6 +29,7 @@ type lambdaPayloadContactData struct {
 	CustomerEndpoint  lambdaPayloadContactEndpoint `json:"CustomerEndpoint"`
 	SystemEndpoint    lambdaPayloadContactEndpoint `json:"SystemEndpoint"`
 	InstanceARN       string                       `json:"InstanceARN"`
+	LanguageCode      string                       `json:"LanguageCode"`
 	Queue             interface{}                  `json:"Queue"`
 }
 
@@ -38,8 +39,29 @@ type lambdaPayloadContactEndpoint struct {
 }
 
 type lambdaPayloadQueue struct {
-	Name string `json:"Name"`
-	ARN  string `json:"ARN"`
+	Name             string                        `json:"Name"`
+	ARN              string                        `json:"ARN"`
+	OutboundCallerID *lambdaPayloadContactEndpoint `json:"OutboundCallerId,omitempty"`
+}
+
+// LambdaRequest describes a single invocation of a lambda.
+// It is carried by the context given to the lambda's handler (see LambdaRequestFromContext).
+type LambdaRequest struct {
+	// RequestID is unique to the invocation.
+	RequestID string
+	// FunctionARN is the ARN the flow invoked.
+	FunctionARN string
+	// ContactID is the ID of the contact that invoked the lambda.
+	ContactID string
+}
+
+type lambdaRequestKey struct{}
+
+// LambdaRequestFromContext gets details of the invocation from the context given to a lambda handler.
+// The context's deadline is set by the time limit of the block that invoked the lambda.
+func LambdaRequestFromContext(ctx context.Context) (LambdaRequest, bool) {
+	r, ok := ctx.Value(lambdaRequestKey{}).(LambdaRequest)
+	return r, ok
 }
 
 // validateLambda checks that a function has the signature required for execution by an invokeExternalResource block.
@@ -71,7 +93,9 @@ func validateLambda(fn interface{}) error {
 	return nil
 }
 
-func invokeLambda(fn interface{}, inJSON string) (outJSON string, outErr error, err error) {
+// invokeLambda runs a lambda handler with the given JSON as input.
+// It gives up waiting for the handler when ctx is done, returning the context's error.
+func invokeLambda(ctx context.Context, fn interface{}, inJSON string) (outJSON string, outErr error, err error) {
 	fnv := reflect.ValueOf(fn)
 	inputt := reflect.TypeOf(fn).In(1)
 	in := reflect.New(inputt)
@@ -79,10 +103,20 @@ func invokeLambda(fn interface{}, inJSON string) (outJSON string, outErr error,
 	if err != nil {
 		return
 	}
-	response := fnv.Call([]reflect.Value{
-		reflect.ValueOf(context.Background()),
-		in.Elem(),
-	})
+	// The handler is left running if it overruns, as a real lambda would be.
+	done := make(chan []reflect.Value, 1)
+	go func() {
+		done <- fnv.Call([]reflect.Value{
+			reflect.ValueOf(ctx),
+			in.Elem(),
+		})
+	}()
+	var response []reflect.Value
+	select {
+	case response = <-done:
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
 	if outErr, ok := response[1].Interface().(error); ok && outErr != nil {
 		return "", outErr, nil
 	}
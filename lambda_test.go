@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestValidateLambda(t *testing.T) {
@@ -92,6 +93,7 @@ func TestInvokeLambda(t *testing.T) {
 		desc      string
 		in        string
 		fn        interface{}
+		timeout   time.Duration
 		expOut    string
 		expOutErr string
 		expErr    string
@@ -150,10 +152,26 @@ func TestInvokeLambda(t *testing.T) {
 			},
 			expOut: `{"greet":"hello"}`,
 		},
+		{
+			desc:    "timeout",
+			in:      validIn,
+			timeout: 10 * time.Millisecond,
+			fn: func(ctx context.Context, in LambdaPayload) (out struct{ Greet string }, err error) {
+				time.Sleep(time.Second)
+				return
+			},
+			expErr: "context deadline exceeded",
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
-			out, outErr, err := invokeLambda(tC.fn, tC.in)
+			ctx := context.Background()
+			if tC.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tC.timeout)
+				defer cancel()
+			}
+			out, outErr, err := invokeLambda(ctx, tC.fn, tC.in)
 			var outErrStr, errStr string
 			if outErr != nil {
 				outErrStr = outErr.Error()
@@ -0,0 +1,44 @@
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type changeRoutingPriority flow.Module
+
+type changeRoutingPriorityParams struct {
+	QueuePriority              *interface{}
+	QueueTimeAdjustmentSeconds *interface{}
+}
+
+func (m changeRoutingPriority) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleChangeRoutingPriority {
+		return nil, fmt.Errorf("module of type %s being run as changeRoutingPriority", m.Type)
+	}
+	p := changeRoutingPriorityParams{}
+	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	priority, age := call.GetRoutingPriority()
+	if p.QueuePriority != nil {
+		priority, err = strconv.Atoi(fmt.Sprintf("%v", *p.QueuePriority))
+		if err != nil || priority < 1 {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+	}
+	if p.QueueTimeAdjustmentSeconds != nil {
+		var secs int
+		secs, err = strconv.Atoi(fmt.Sprintf("%v", *p.QueueTimeAdjustmentSeconds))
+		if err != nil {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		age = time.Duration(secs) * time.Second
+	}
+	call.SetRoutingPriority(priority, age)
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
@@ -0,0 +1,161 @@
+package module
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+func TestChangeRoutingPriority(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonPriority := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"ChangeRoutingPriority",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"QueuePriority","value":"1"}]
+	}`
+	jsonDynamicPriority := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"ChangeRoutingPriority",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"QueuePriority","value":"vipPriority","namespace":"External"}]
+	}`
+	jsonAge := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"ChangeRoutingPriority",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"QueueTimeAdjustmentSeconds","value":120}]
+	}`
+	jsonBadPriority := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"ChangeRoutingPriority",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"QueuePriority","value":"urgent"}]
+	}`
+	jsonBadAge := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"ChangeRoutingPriority",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"QueueTimeAdjustmentSeconds","value":"a while"}]
+	}`
+	withRouting := func(priority int, age time.Duration) *testCallState {
+		st := testCallState{
+			external: map[string]string{"vipPriority": "2"},
+		}.init()
+		st.SetRoutingPriority(priority, age)
+		return st
+	}
+	testCases := []struct {
+		desc        string
+		module      string
+		state       *testCallState
+		exp         string
+		expPriority int
+		expAge      time.Duration
+		expEvt      []event.Event
+		expErr      string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as changeRoutingPriority",
+		},
+		{
+			desc:        "static priority",
+			module:      jsonPriority,
+			state:       withRouting(5, 10*time.Second),
+			exp:         "00000000-0000-4000-0000-000000000001",
+			expPriority: 1,
+			expAge:      10 * time.Second,
+			expEvt:      []event.Event{},
+		},
+		{
+			desc:        "dynamic priority",
+			module:      jsonDynamicPriority,
+			state:       withRouting(5, 0),
+			exp:         "00000000-0000-4000-0000-000000000001",
+			expPriority: 2,
+			expEvt:      []event.Event{},
+		},
+		{
+			desc:        "age",
+			module:      jsonAge,
+			state:       withRouting(3, 0),
+			exp:         "00000000-0000-4000-0000-000000000001",
+			expPriority: 3,
+			expAge:      2 * time.Minute,
+			expEvt:      []event.Event{},
+		},
+		{
+			desc:        "invalid priority",
+			module:      jsonBadPriority,
+			state:       withRouting(5, 0),
+			exp:         "00000000-0000-4000-0000-000000000002",
+			expPriority: 5,
+			expEvt:      []event.Event{},
+		},
+		{
+			desc:        "invalid age",
+			module:      jsonBadAge,
+			state:       withRouting(5, 0),
+			exp:         "00000000-0000-4000-0000-000000000002",
+			expPriority: 5,
+			expEvt:      []event.Event{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod changeRoutingPriority
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if priority, age := state.GetRoutingPriority(); priority != tC.expPriority || age != tC.expAge {
+				t.Errorf("expected priority %d and age %v but got priority %d and age %v", tC.expPriority, tC.expAge, priority, age)
+			}
+			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
+				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package module
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -25,6 +26,10 @@ func (m checkAttribute) Run(call CallConnector) (next *flow.ModuleID, err error)
 		return
 	}
 	v, err := pr.get(flow.ModuleParameterNamespace(p.Namespace), p.Attribute)
+	var paramErr *Error
+	if errors.As(err, &paramErr) {
+		paramErr.Parameter = "Namespace"
+	}
 	if err != nil {
 		return
 	}
@@ -56,7 +61,7 @@ func evaluateConditions(c flow.ModuleBranchList, v string) (*flow.ModuleID, erro
 		case flow.ConditionLTE:
 			pass = bool((numeric && vn <= cvn) || (!numeric && v <= val))
 		default:
-			return nil, fmt.Errorf("unhandled condition type: %s", c.ConditionType)
+			return nil, newError(ErrInvalidParameter, "ConditionType", "unhandled condition type: %s", c.ConditionType)
 		}
 		if pass {
 			return &c.Transition, nil
@@ -2,6 +2,7 @@ package module
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -68,12 +69,14 @@ func TestCheckAttribute(t *testing.T) {
 		]
 	}`
 	testCases := []struct {
-		desc   string
-		module string
-		state  *testCallState
-		exp    string
-		expEvt []event.Event
-		expErr string
+		desc     string
+		module   string
+		state    *testCallState
+		exp      string
+		expEvt   []event.Event
+		expErr   string
+		expKind  error
+		expParam string
 	}{
 		{
 			desc:   "wrong module",
@@ -90,19 +93,23 @@ func TestCheckAttribute(t *testing.T) {
 			expErr: "missing parameter Namespace",
 		},
 		{
-			desc:   "bad namespace",
-			module: jsonBadNamespace,
-			state:  testCallState{}.init(),
-			exp:    "",
-			expErr: "unknown namespace: S3",
+			desc:     "bad namespace",
+			module:   jsonBadNamespace,
+			state:    testCallState{}.init(),
+			exp:      "",
+			expErr:   "unknown namespace: S3",
+			expKind:  ErrInvalidParameter,
+			expParam: "Namespace",
 		},
 		{
-			desc:   "unknown condition",
-			module: jsonBadCondition,
-			state:  testCallState{}.init(),
-			exp:    "",
-			expEvt: []event.Event{},
-			expErr: "unhandled condition type: StartsWith",
+			desc:     "unknown condition",
+			module:   jsonBadCondition,
+			state:    testCallState{}.init(),
+			exp:      "",
+			expEvt:   []event.Event{},
+			expErr:   "unhandled condition type: StartsWith",
+			expKind:  ErrInvalidParameter,
+			expParam: "ConditionType",
 		},
 		{
 			desc:   "numeric comparison match",
@@ -168,6 +175,15 @@ func TestCheckAttribute(t *testing.T) {
 			if errStr != tC.expErr {
 				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
 			}
+			if tC.expKind != nil {
+				var blockErr *Error
+				if !errors.As(err, &blockErr) || !errors.Is(err, tC.expKind) {
+					t.Fatalf("expected error of kind '%v' but got %#v", tC.expKind, err)
+				}
+				if blockErr.Parameter != tC.expParam {
+					t.Errorf("expected error in parameter '%s' but got '%s'", tC.expParam, blockErr.Parameter)
+				}
+			}
 			nextStr := ""
 			if next != nil {
 				nextStr = string(*next)
@@ -20,7 +20,11 @@ func (m checkHoursOfOperation) Run(call CallConnector) (next *flow.ModuleID, err
 	var inHours bool
 	var ihErr error
 	if ok {
-		inHours, ihErr = call.IsInHours(h.ResourceName, false)
+		name := hoursName(h, call)
+		if name == nil {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		inHours, ihErr = call.IsInHours(*name, false)
 	} else if q != nil {
 		inHours, ihErr = call.IsInHours(*q, true)
 	} else {
@@ -36,3 +40,20 @@ func (m checkHoursOfOperation) Run(call CallConnector) (next *flow.ModuleID, err
 	}
 	return m.Branches.GetLink(flow.BranchTrue), nil
 }
+
+// hoursName gives the name of the hours of operation given in the Hours parameter.
+// Hours chosen in the block carry their name. Hours taken from an attribute are looked up by ARN.
+func hoursName(p flow.ModuleParameter, call CallConnector) *string {
+	if isStatic(p) && p.ResourceName != "" {
+		return &p.ResourceName
+	}
+	val, err := parameterResolver{call}.resolve(p)
+	if err != nil {
+		return nil
+	}
+	arn, ok := val.(string)
+	if !ok {
+		return nil
+	}
+	return call.GetHoursName(arn)
+}
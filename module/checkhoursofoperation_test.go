@@ -32,6 +32,19 @@ func TestCheckHoursOfOperation(t *testing.T) {
 			{ "name": "Hours", "value": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/8f135e8d-278d-4c7a-9415-3b3b79a5d07c", "resourceName": "Custom Hours" }
 		]
 	}`
+	jsonDynamicHours := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"CheckHoursOfOperation",
+		"branches":[
+			{"condition":"True", "transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"False","transition":"00000000-0000-4000-0000-000000000002"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000003"}
+		],
+		"parameters":[
+			{ "name": "Hours", "value": "hours", "namespace": "User Defined" }
+		]
+	}`
+	hoursARN := "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/operating-hours/8f135e8d-278d-4c7a-9415-3b3b79a5d07c"
 	jsonBadMod := `{
 		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
 		"type":"Disconnect"
@@ -127,6 +140,33 @@ func TestCheckHoursOfOperation(t *testing.T) {
 			}.init(),
 			exp: "00000000-0000-4000-0000-000000000002",
 		},
+		{
+			desc:   "In hours - dynamic hours",
+			module: jsonDynamicHours,
+			state: testCallState{
+				contactData: map[string]string{"hours": hoursARN},
+				hours:       map[string]string{hoursARN: "Custom Hours"},
+				inHours: func(n string, q bool, ct time.Time) (bool, error) {
+					if n != "Custom Hours" {
+						t.Errorf("Expected hours name of %s but got %s", "Custom Hours", n)
+					}
+					return true, nil
+				},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+		},
+		{
+			desc:   "unknown dynamic hours",
+			module: jsonDynamicHours,
+			state: testCallState{
+				contactData: map[string]string{"hours": hoursARN},
+				inHours: func(n string, q bool, ct time.Time) (bool, error) {
+					t.Error("Expected inHours not to be called for unknown hours, but it was called")
+					return true, nil
+				},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000003",
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -0,0 +1,29 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type checkOutboundCallStatus flow.Module
+
+func (m checkOutboundCallStatus) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleCheckOutboundCallStatus {
+		return nil, fmt.Errorf("module of type %s being run as checkOutboundCallStatus", m.Type)
+	}
+	status := call.GetSystem(flow.SystemAnsweringMachineStatus)
+	if status == nil {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	switch *status {
+	case "HUMAN_ANSWERED":
+		return m.Branches.GetLink(flow.BranchCallAnswered), nil
+	case "VOICEMAIL_BEEP":
+		return m.Branches.GetLink(flow.BranchVoicemailBeep), nil
+	case "VOICEMAIL_NO_BEEP":
+		return m.Branches.GetLink(flow.BranchVoicemailNoBeep), nil
+	default:
+		return m.Branches.GetLink(flow.BranchNotDetected), nil
+	}
+}
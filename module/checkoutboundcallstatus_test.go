@@ -0,0 +1,97 @@
+package module
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+func TestCheckOutboundCallStatus(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Disconnect"
+	}`
+	jsonOK := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"CheckOutboundCallStatus",
+		"branches":[
+			{"condition":"CallAnswered","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"VoicemailBeep","transition":"00000000-0000-4000-0000-000000000002"},
+			{"condition":"VoicemailNoBeep","transition":"00000000-0000-4000-0000-000000000003"},
+			{"condition":"NotDetected","transition":"00000000-0000-4000-0000-000000000004"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000005"}
+		],
+		"parameters":[]
+	}`
+	testCases := []struct {
+		desc   string
+		module string
+		status string
+		exp    string
+		expErr string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Disconnect being run as checkOutboundCallStatus",
+		},
+		{
+			desc:   "not an outbound call",
+			module: jsonOK,
+			exp:    "00000000-0000-4000-0000-000000000005",
+		},
+		{
+			desc:   "answered",
+			module: jsonOK,
+			status: "HUMAN_ANSWERED",
+			exp:    "00000000-0000-4000-0000-000000000001",
+		},
+		{
+			desc:   "voicemail with beep",
+			module: jsonOK,
+			status: "VOICEMAIL_BEEP",
+			exp:    "00000000-0000-4000-0000-000000000002",
+		},
+		{
+			desc:   "voicemail without beep",
+			module: jsonOK,
+			status: "VOICEMAIL_NO_BEEP",
+			exp:    "00000000-0000-4000-0000-000000000003",
+		},
+		{
+			desc:   "undetermined",
+			module: jsonOK,
+			status: "AMD_UNRESOLVED",
+			exp:    "00000000-0000-4000-0000-000000000004",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod checkOutboundCallStatus
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := testCallState{}.init()
+			if tC.status != "" {
+				state.system[flow.SystemAnsweringMachineStatus] = tC.status
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+		})
+	}
+}
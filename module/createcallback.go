@@ -0,0 +1,57 @@
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type createCallback flow.Module
+
+type createCallbackParams struct {
+	InitialDelaySeconds *interface{}
+}
+
+func (m createCallback) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleCreateCallback {
+		return nil, fmt.Errorf("module of type %s being run as createCallback", m.Type)
+	}
+	p := createCallbackParams{}
+	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	queue := call.GetSystem(flow.SystemQueueName)
+	arn := call.GetSystem(flow.SystemQueueARN)
+	if queue == nil || arn == nil {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	// Without a callback number, the customer is called back on the number they called from.
+	tel := call.GetSystem(flow.SystemCustomerCallback)
+	if tel == nil {
+		tel = call.GetSystem(flow.SystemCustomerNumber)
+	}
+	if tel == nil || *tel == "" {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	cb := Callback{
+		Tel:       *tel,
+		QueueARN:  *arn,
+		QueueName: *queue,
+	}
+	if p.InitialDelaySeconds != nil {
+		secs, err := strconv.Atoi(fmt.Sprintf("%v", *p.InitialDelaySeconds))
+		if err != nil || secs < 0 {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		cb.InitialDelay = time.Duration(secs) * time.Second
+	}
+	id, err := call.CreateCallback(cb)
+	if err != nil {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	call.Emit(contactCreated(call, id, "CALLBACK"))
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
@@ -0,0 +1,165 @@
+package module
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+func TestCreateCallback(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonOK := `{
+		"id":"9ec9348c-c517-403f-935b-9c3bb8e35c1b",
+		"type":"CreateCallback",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"InitialDelaySeconds","value":5},
+			{"name":"RetryDelaySeconds","value":600},
+			{"name":"MaxRetryAttempts","value":1}
+		]
+	}`
+	jsonBadDelay := `{
+		"id":"9ec9348c-c517-403f-935b-9c3bb8e35c1b",
+		"type":"CreateCallback",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"InitialDelaySeconds","value":"soon"}]
+	}`
+	queueARN := "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001"
+	system := func(extra map[flow.SystemKey]string) map[flow.SystemKey]string {
+		sys := map[flow.SystemKey]string{
+			flow.SystemContactID:        "contact-0",
+			flow.SystemInitialContactID: "contact-0",
+			flow.SystemCustomerNumber:   "+447878123456",
+			flow.SystemQueueName:        "Sales",
+			flow.SystemQueueARN:         queueARN,
+		}
+		for k, v := range extra {
+			sys[k] = v
+		}
+		return sys
+	}
+	created := event.ContactCreatedEvent{
+		ContactID:         "contact-1",
+		PreviousContactID: "contact-0",
+		InitialContactID:  "contact-0",
+		InitiationMethod:  "CALLBACK",
+		Channel:           "VOICE",
+	}
+	testCases := []struct {
+		desc         string
+		module       string
+		state        *testCallState
+		exp          string
+		expErr       string
+		expCallbacks []Callback
+		expEvt       []event.Event
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as createCallback",
+		},
+		{
+			desc:   "success - customer number",
+			module: jsonOK,
+			state:  testCallState{system: system(nil)}.init(),
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expCallbacks: []Callback{{
+				Tel:          "+447878123456",
+				QueueARN:     queueARN,
+				QueueName:    "Sales",
+				InitialDelay: 5 * time.Second,
+			}},
+			expEvt: []event.Event{created},
+		},
+		{
+			desc:   "success - callback number",
+			module: jsonOK,
+			state: testCallState{system: system(map[flow.SystemKey]string{
+				flow.SystemCustomerCallback: "+447878654321",
+			})}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expCallbacks: []Callback{{
+				Tel:          "+447878654321",
+				QueueARN:     queueARN,
+				QueueName:    "Sales",
+				InitialDelay: 5 * time.Second,
+			}},
+			expEvt: []event.Event{created},
+		},
+		{
+			desc:   "no queue",
+			module: jsonOK,
+			state: testCallState{system: map[flow.SystemKey]string{
+				flow.SystemCustomerNumber: "+447878123456",
+			}}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "bad delay",
+			module: jsonBadDelay,
+			state:  testCallState{system: system(nil)}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "creation fails",
+			module: jsonOK,
+			state: testCallState{
+				system:      system(nil),
+				callbackErr: errors.New("queue at capacity: Sales"),
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod createCallback
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if !reflect.DeepEqual(tC.expCallbacks, state.callbacks) {
+				t.Errorf("expected callbacks of %v but got %v", tC.expCallbacks, state.callbacks)
+			}
+			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
+				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type createTask flow.Module
+
+type createTaskParams struct {
+	Name        string
+	Description *string
+	Reference   []flow.KeyValue
+}
+
+func (m createTask) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleCreateTask {
+		return nil, fmt.Errorf("module of type %s being run as createTask", m.Type)
+	}
+	cfid, ok := m.Parameters.Get("ContactFlowId")
+	if !ok {
+		return nil, newError(ErrMissingParameter, "ContactFlowId", "missing ContactFlowId parameter")
+	}
+	pr := parameterResolver{call}
+	p := createTaskParams{}
+	err = pr.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	_, flowName, err := resolveFlow(cfid, call)
+	if err != nil {
+		return nil, err
+	}
+	if flowName == nil || p.Name == "" {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	scheduled, ok, err := m.scheduledTime(pr)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	task := Task{
+		Name:          pr.jsonPath(p.Name),
+		FlowName:      *flowName,
+		References:    map[string]string{},
+		ScheduledTime: scheduled,
+	}
+	if p.Description != nil {
+		task.Description = pr.jsonPath(*p.Description)
+	}
+	for _, r := range p.Reference {
+		task.References[r.K] = r.V
+	}
+	id, err := call.CreateTask(task)
+	if err != nil {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	call.SetSystem(flow.SystemTaskContactID, id)
+	call.Emit(event.TaskCreatedEvent{
+		ContactID:     id,
+		Name:          task.Name,
+		Description:   task.Description,
+		FlowName:      task.FlowName,
+		References:    task.References,
+		ScheduledTime: task.ScheduledTime,
+	})
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
+
+// scheduledTime reads the optional ScheduledTime parameter.
+// It may be given in epoch seconds or as an RFC 3339 timestamp. ok is false if the value cannot be read as either.
+func (m createTask) scheduledTime(pr parameterResolver) (t time.Time, ok bool, err error) {
+	p, found := m.Parameters.Get("ScheduledTime")
+	if !found {
+		return time.Time{}, true, nil
+	}
+	val, err := pr.resolve(p)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	switch v := val.(type) {
+	case nil:
+		return time.Time{}, true, nil
+	case float64:
+		return time.Unix(int64(v), 0).UTC(), true, nil
+	case string:
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(secs, 0).UTC(), true, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		return t, err == nil, nil
+	}
+	return time.Time{}, false, nil
+}
@@ -0,0 +1,184 @@
+package module
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+func TestCreateTask(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonBadParam := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"CreateTask",
+		"parameters":[{"name":"Name","value":"Call back"}]
+	}`
+	jsonOK := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"CreateTask",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"ContactFlowId","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/cccccccc-0000-4000-0000-000000000001","resourceName":"Callbacks"},
+			{"name":"Name","value":"Call back $.Attributes.name"},
+			{"name":"Description","value":"Customer asked for a call back"},
+			{"name":"Reference","key":"account","value":"https://example.com/accounts/123"}
+		]
+	}`
+	jsonDynamic := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"CreateTask",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"ContactFlowId","value":"taskFlow","namespace":"User Defined"},
+			{"name":"Name","value":"Call back"},
+			{"name":"ScheduledTime","value":"scheduled","namespace":"User Defined"}
+		]
+	}`
+	flowARN := "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/cccccccc-0000-4000-0000-000000000002"
+	testCases := []struct {
+		desc     string
+		module   string
+		state    *testCallState
+		exp      string
+		expErr   string
+		expTasks []Task
+		expEvt   []event.Event
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as createTask",
+		},
+		{
+			desc:   "missing flow",
+			module: jsonBadParam,
+			expErr: "missing ContactFlowId parameter",
+		},
+		{
+			desc:   "success",
+			module: jsonOK,
+			state: testCallState{
+				contactData: map[string]string{"name": "Edward"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expTasks: []Task{{
+				Name:        "Call back Edward",
+				Description: "Customer asked for a call back",
+				FlowName:    "Callbacks",
+				References:  map[string]string{"account": "https://example.com/accounts/123"},
+			}},
+			expEvt: []event.Event{event.TaskCreatedEvent{
+				ContactID:   "task-1",
+				Name:        "Call back Edward",
+				Description: "Customer asked for a call back",
+				FlowName:    "Callbacks",
+				References:  map[string]string{"account": "https://example.com/accounts/123"},
+			}},
+		},
+		{
+			desc:   "creation fails",
+			module: jsonOK,
+			state: testCallState{
+				taskErr: errors.New("flow not found"),
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "dynamic - unknown flow",
+			module: jsonDynamic,
+			state: testCallState{
+				contactData: map[string]string{"taskFlow": flowARN},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "dynamic - scheduled in epoch seconds",
+			module: jsonDynamic,
+			state: testCallState{
+				contactData: map[string]string{"taskFlow": flowARN, "scheduled": "1577869200"},
+				flows:       map[string]string{flowARN: "Callbacks"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expTasks: []Task{{
+				Name:          "Call back",
+				FlowName:      "Callbacks",
+				References:    map[string]string{},
+				ScheduledTime: time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			desc:   "dynamic - scheduled as timestamp",
+			module: jsonDynamic,
+			state: testCallState{
+				contactData: map[string]string{"taskFlow": flowARN, "scheduled": "2020-01-01T09:00:00Z"},
+				flows:       map[string]string{flowARN: "Callbacks"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expTasks: []Task{{
+				Name:          "Call back",
+				FlowName:      "Callbacks",
+				References:    map[string]string{},
+				ScheduledTime: time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			desc:   "dynamic - bad scheduled time",
+			module: jsonDynamic,
+			state: testCallState{
+				contactData: map[string]string{"taskFlow": flowARN, "scheduled": "tomorrow"},
+				flows:       map[string]string{flowARN: "Callbacks"},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod createTask
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if !reflect.DeepEqual(tC.expTasks, state.tasks) {
+				t.Errorf("expected tasks of '%v' but got '%v'", tC.expTasks, state.tasks)
+			}
+			if tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events) {
+				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// Kinds of Error. Use errors.Is to check which kind an error is.
+var (
+	// ErrMissingParameter is a required block parameter that is not set.
+	ErrMissingParameter = errorKind("missing parameter")
+	// ErrTypeMismatch is a block parameter that has the wrong type.
+	ErrTypeMismatch = errorKind("type mismatch")
+	// ErrInvalidParameter is a block parameter with a value the block cannot use.
+	ErrInvalidParameter = errorKind("invalid parameter")
+	// ErrUnknownTarget is a block with a target it does not support.
+	ErrUnknownTarget = errorKind("unknown target")
+	// ErrMissingModule is a branch to a block that is not in any loaded flow.
+	ErrMissingModule = errorKind("missing module")
+	// ErrLambdaInvocation is a lambda whose response cannot be used.
+	ErrLambdaInvocation = errorKind("lambda invocation failed")
+)
+
+type errorKind string
+
+func (k errorKind) Error() string {
+	return string(k)
+}
+
+// Error is an error that stops a block from running, which ends the call.
+// Runners fill in the kind and parameter. The block and flow are filled in by the call that ran it.
+type Error struct {
+	// Kind is one of the Err values of this package, or nil if the error is of no particular kind.
+	Kind error
+	// ModuleID is the ID of the block that failed. For ErrMissingModule, it is the block that branched to the missing one, if there was one.
+	ModuleID flow.ModuleID
+	// ModuleType is the type of the block that failed.
+	ModuleType flow.ModuleType
+	// Flow is the name of the flow the block is in. For ErrMissingModule, it is the flow of the block that branched to it.
+	Flow string
+	// Parameter is the name of the block parameter at fault, if there is one.
+	Parameter string
+	// Target is the ID of the block that could not be found, for ErrMissingModule.
+	Target flow.ModuleID
+	// Err describes what went wrong.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.ModuleID == "" {
+		return e.Err.Error()
+	}
+	block := fmt.Sprintf("block %s", e.ModuleID)
+	if e.ModuleType != "" {
+		block = fmt.Sprintf("%s block %s", e.ModuleType, e.ModuleID)
+	}
+	if e.Flow != "" {
+		block = fmt.Sprintf("%s in %s", block, e.Flow)
+	}
+	return fmt.Sprintf("%s: %v", block, e.Err)
+}
+
+// Unwrap gives the error that describes what went wrong.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the given kind.
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// newError makes an Error of the given kind, described by the formatted message.
+func newError(kind error, parameter string, format string, a ...interface{}) *Error {
+	return &Error{
+		Kind:      kind,
+		Parameter: parameter,
+		Err:       fmt.Errorf(format, a...),
+	}
+}
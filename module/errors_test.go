@@ -0,0 +1,74 @@
+package module
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		err     *Error
+		exp     string
+		expKind error
+	}{
+		{
+			desc: "not yet run",
+			err:  newError(ErrMissingParameter, "Text", "missing parameter %s", "Text"),
+			exp:  "missing parameter Text",
+		},
+		{
+			desc: "block",
+			err: &Error{
+				Kind:       ErrInvalidParameter,
+				ModuleID:   "00000000-0000-4000-0000-000000000001",
+				ModuleType: "GetUserInput",
+				Flow:       "Main menu",
+				Parameter:  "Timeout",
+				Err:        errors.New("invalid Timeout: five"),
+			},
+			exp:     "GetUserInput block 00000000-0000-4000-0000-000000000001 in Main menu: invalid Timeout: five",
+			expKind: ErrInvalidParameter,
+		},
+		{
+			desc: "missing block",
+			err: &Error{
+				Kind:       ErrMissingModule,
+				ModuleID:   "00000000-0000-4000-0000-000000000001",
+				ModuleType: "GetUserInput",
+				Target:     "00000000-0000-4000-0000-000000000002",
+				Err:        errors.New("missing module: 00000000-0000-4000-0000-000000000002"),
+			},
+			exp:     "GetUserInput block 00000000-0000-4000-0000-000000000001: missing module: 00000000-0000-4000-0000-000000000002",
+			expKind: ErrMissingModule,
+		},
+		{
+			desc: "no kind",
+			err: &Error{
+				ModuleID:   "00000000-0000-4000-0000-000000000003",
+				ModuleType: "SetRecordingBehavior",
+				Err:        errors.New("module of type SetRecordingBehavior being run as checkAttribute"),
+			},
+			exp: "SetRecordingBehavior block 00000000-0000-4000-0000-000000000003: module of type SetRecordingBehavior being run as checkAttribute",
+		},
+	}
+	kinds := []error{ErrMissingParameter, ErrTypeMismatch, ErrInvalidParameter, ErrUnknownTarget, ErrMissingModule, ErrLambdaInvocation}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if got := tC.err.Error(); got != tC.exp {
+				t.Errorf("expected error of '%s' but got '%s'", tC.exp, got)
+			}
+			if tC.expKind == nil {
+				tC.expKind = tC.err.Kind
+			}
+			for _, k := range kinds {
+				if is := errors.Is(tC.err, k); is != (k == tC.expKind) {
+					t.Errorf("expected errors.Is(err, %v) to be %t", k, !is)
+				}
+			}
+			if !errors.Is(tC.err, tC.err.Err) {
+				t.Error("expected error to wrap its description")
+			}
+		})
+	}
+}
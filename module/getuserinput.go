@@ -10,6 +10,9 @@ import (
 
 type getUserInput flow.Module
 
+// lexTimeout is how long a Lex bot listens for the caller to start speaking.
+const lexTimeout = 5 * time.Second
+
 type getUserInputParams struct {
 	Text             string
 	Timeout          string
@@ -17,10 +20,19 @@ type getUserInputParams struct {
 	TextToSpeechType string
 }
 
+type getUserInputLexParams struct {
+	Text             string
+	TextToSpeechType string
+	BotName          string
+}
+
 func (m getUserInput) Run(call CallConnector) (next *flow.ModuleID, err error) {
 	if m.Type != flow.ModuleGetUserInput {
 		return nil, fmt.Errorf("module of type %s being run as getUserInput", m.Type)
 	}
+	if m.Target == flow.TargetLex {
+		return m.runLex(call)
+	}
 	pr := parameterResolver{call}
 	p := getUserInputParams{}
 	err = pr.unmarshal(m.Parameters, &p)
@@ -31,18 +43,53 @@ func (m getUserInput) Run(call CallConnector) (next *flow.ModuleID, err error) {
 		return m.Branches.GetLink(flow.BranchError), nil
 	}
 	txt := pr.jsonPath(p.Text)
-	call.Send(txt, p.TextToSpeechType == "ssml")
+	call.SendOptions(txt, p.TextToSpeechType == "ssml", choices(m.Branches))
 	md, err := strconv.Atoi(p.MaxDigits)
 	if err != nil {
-		return nil, fmt.Errorf("invalid MaxDigits: %s", p.MaxDigits)
+		return nil, newError(ErrInvalidParameter, "MaxDigits", "invalid MaxDigits: %s", p.MaxDigits)
 	}
 	tm, err := strconv.Atoi(p.Timeout)
 	if err != nil {
-		return nil, fmt.Errorf("invalid Timeout: %s", p.Timeout)
+		return nil, newError(ErrInvalidParameter, "Timeout", "invalid Timeout: %s", p.Timeout)
 	}
-	in, ok := call.Receive(md, time.Duration(tm)*time.Second, rune(0))
+	in, ok := call.Receive(md, time.Duration(tm)*time.Second, defaultInterdigitTimeout, "")
 	if !ok {
 		return m.Branches.GetLink(flow.BranchTimeout), nil
 	}
 	return evaluateConditions(m.Branches, in)
 }
+
+// choices gives the entries that match the block's conditions, which are offered to a customer on chat.
+func choices(c flow.ModuleBranchList) []string {
+	opts := []string{}
+	for _, b := range c.List(flow.BranchEvaluate) {
+		if b.ConditionType == flow.ConditionEquals {
+			opts = append(opts, fmt.Sprintf("%v", b.ConditionValue))
+		}
+	}
+	return opts
+}
+
+// runLex takes speech (or a key press) from the caller and passes it to a Lex bot.
+// The intent the bot finds is matched against the block's conditions.
+func (m getUserInput) runLex(call CallConnector) (next *flow.ModuleID, err error) {
+	pr := parameterResolver{call}
+	p := getUserInputLexParams{}
+	err = pr.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	if p.Text == "" {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	call.Send(pr.jsonPath(p.Text), p.TextToSpeechType == "ssml")
+	utterance, ok := call.ReceiveSpeech(lexTimeout)
+	if !ok {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	intent, err := call.InvokeLexBot(p.BotName, utterance)
+	if err != nil {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	return evaluateConditions(m.Branches, intent)
+}
@@ -42,6 +42,27 @@ func TestGetUserInput(t *testing.T) {
 		],
 		"target":"Digits"
 	}`
+	jsonLex := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"GetUserInput",
+		"branches":[
+			{"condition":"Evaluate","conditionType":"Equals","conditionValue":"CheckBalance","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Evaluate","conditionType":"Equals","conditionValue":"MakePayment","transition":"00000000-0000-4000-0000-000000000002"},
+			{"condition":"NoMatch","transition":"00000000-0000-4000-0000-000000000004"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000005"}
+		],
+		"parameters":[
+			{"name":"Text","value":"How can I help?"},
+			{"name":"TextToSpeechType","value":"text"},
+			{"name":"BotName","value":"Banking"},
+			{"name":"BotAlias","value":"$LATEST"},
+			{"name":"BotRegion","value":"eu-west-2"}
+		],
+		"target":"Lex"
+	}`
+	lexBots := map[string]map[string]string{
+		"Banking": {"check my balance": "CheckBalance", "pay my bill": "MakePayment", "1": "CheckBalance"},
+	}
 	testCases := []struct {
 		desc          string
 		module        string
@@ -50,8 +71,10 @@ func TestGetUserInput(t *testing.T) {
 		exp           string
 		expErr        string
 		expPrompt     string
+		expOptions    []string
 		expRcvTimeout time.Duration
 		expRcvCount   int
+		expRcvSpeech  bool
 		expEvt        []event.Event
 	}{
 		{
@@ -88,6 +111,7 @@ func TestGetUserInput(t *testing.T) {
 				},
 			}.init(),
 			expPrompt:     "<speak>Enter a number, Dr Customer</speak>",
+			expOptions:    []string{"1", "2"},
 			expRcvCount:   1,
 			expRcvTimeout: 5 * time.Second,
 			expEvt:        []event.Event{},
@@ -103,6 +127,7 @@ func TestGetUserInput(t *testing.T) {
 				},
 			}.init(),
 			expPrompt:     "<speak>Enter a number</speak>",
+			expOptions:    []string{"1", "2"},
 			expRcvCount:   1,
 			expRcvTimeout: 5 * time.Second,
 			expEvt:        []event.Event{},
@@ -118,10 +143,65 @@ func TestGetUserInput(t *testing.T) {
 				},
 			}.init(),
 			expPrompt:     "<speak>Enter a number</speak>",
+			expOptions:    []string{"1", "2"},
 			expRcvCount:   1,
 			expRcvTimeout: 5 * time.Second,
 			expEvt:        []event.Event{},
 		},
+		{
+			desc:          "lex - matching intent",
+			module:        jsonLex,
+			entry:         "check my balance",
+			exp:           "00000000-0000-4000-0000-000000000001",
+			state:         testCallState{lexBots: lexBots}.init(),
+			expPrompt:     "How can I help?",
+			expRcvTimeout: 5 * time.Second,
+			expRcvSpeech:  true,
+			expEvt:        []event.Event{},
+		},
+		{
+			desc:          "lex - key press",
+			module:        jsonLex,
+			entry:         "1",
+			exp:           "00000000-0000-4000-0000-000000000001",
+			state:         testCallState{lexBots: lexBots}.init(),
+			expPrompt:     "How can I help?",
+			expRcvTimeout: 5 * time.Second,
+			expRcvSpeech:  true,
+			expEvt:        []event.Event{},
+		},
+		{
+			desc:          "lex - no intent",
+			module:        jsonLex,
+			entry:         "what is the weather",
+			exp:           "00000000-0000-4000-0000-000000000004",
+			state:         testCallState{lexBots: lexBots}.init(),
+			expPrompt:     "How can I help?",
+			expRcvTimeout: 5 * time.Second,
+			expRcvSpeech:  true,
+			expEvt:        []event.Event{},
+		},
+		{
+			desc:          "lex - silence",
+			module:        jsonLex,
+			entry:         "Timeout",
+			exp:           "00000000-0000-4000-0000-000000000005",
+			state:         testCallState{lexBots: lexBots}.init(),
+			expPrompt:     "How can I help?",
+			expRcvTimeout: 5 * time.Second,
+			expRcvSpeech:  true,
+			expEvt:        []event.Event{},
+		},
+		{
+			desc:          "lex - unknown bot",
+			module:        jsonLex,
+			entry:         "check my balance",
+			exp:           "00000000-0000-4000-0000-000000000005",
+			expPrompt:     "How can I help?",
+			expRcvTimeout: 5 * time.Second,
+			expRcvSpeech:  true,
+			expEvt:        []event.Event{},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -153,12 +233,21 @@ func TestGetUserInput(t *testing.T) {
 			if state.o != tC.expPrompt {
 				t.Errorf("expected prompt of '%s' but got '%s'", tC.expPrompt, state.o)
 			}
+			if !reflect.DeepEqual(state.oOptions, tC.expOptions) {
+				t.Errorf("expected options of %v but got %v", tC.expOptions, state.oOptions)
+			}
 			if state.rcv.count != tC.expRcvCount {
 				t.Errorf("expected receive count of %d but got %d", state.rcv.count, tC.expRcvCount)
 			}
 			if state.rcv.timeout != tC.expRcvTimeout {
 				t.Errorf("expected receive timeout of %d but got %d", state.rcv.timeout, tC.expRcvTimeout)
 			}
+			if interruptible := tC.expPrompt != "" && !tC.expRcvSpeech; state.oInterruptible != interruptible {
+				t.Errorf("expected prompt interruptible to be %t but got %t", interruptible, state.oInterruptible)
+			}
+			if state.rcv.speech != tC.expRcvSpeech {
+				t.Errorf("expected speech input to be %t but got %t", tC.expRcvSpeech, state.rcv.speech)
+			}
 			if state.rcv.encrypt != false {
 				t.Error("expected not to get encrypted entry, but encryption was requested")
 			}
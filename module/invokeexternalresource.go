@@ -23,7 +23,7 @@ func (m invokeExternalResource) Run(call CallConnector) (next *flow.ModuleID, er
 		return nil, fmt.Errorf("module of type %s being run as invokeExternalResource", m.Type)
 	}
 	if m.Target != flow.TargetLambda {
-		return nil, fmt.Errorf("unknown target: %s", m.Target)
+		return nil, newError(ErrUnknownTarget, "", "unknown target: %s", m.Target)
 	}
 	p := invokeExternalResourceParams{}
 	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
@@ -32,7 +32,7 @@ func (m invokeExternalResource) Run(call CallConnector) (next *flow.ModuleID, er
 	}
 	tl, err := strconv.Atoi(p.TimeLimit)
 	if err != nil {
-		return nil, fmt.Errorf("invalid TimeLimit: %s", p.TimeLimit)
+		return nil, newError(ErrInvalidParameter, "TimeLimit", "invalid TimeLimit: %s", p.TimeLimit)
 	}
 	fields := make([]string, len(p.Parameter))
 	for i, p := range p.Parameter {
@@ -50,7 +50,7 @@ func (m invokeExternalResource) Run(call CallConnector) (next *flow.ModuleID, er
 	out := map[string]interface{}{}
 	err = json.Unmarshal([]byte(jsonOut), &out)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal json from lambda: %s", jsonOut)
+		return nil, newError(ErrLambdaInvocation, "FunctionArn", "failed to unmarshal json from lambda: %s", jsonOut)
 	}
 	call.ClearExternal()
 	for k, v := range out {
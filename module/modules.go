@@ -11,8 +11,11 @@ import (
 // CallConnector describes what a module needs to interact with the ongoing call.
 type CallConnector interface {
 	Send(s string, ssml bool)
-	Receive(count int, timeout time.Duration, terminator rune) (string, bool)
-	Encrypt(in string, keyID string, cert []byte) []byte
+	SendInterruptible(s string, ssml bool)
+	SendOptions(s string, ssml bool, options []string)
+	Receive(count int, timeout time.Duration, interdigit time.Duration, terminator string) (string, bool)
+	ReceiveSpeech(timeout time.Duration) (string, bool)
+	Encrypt(in string, keyID string, cert []byte) ([]byte, error)
 	Emit(event event.Event)
 	GetExternal(key string) *string
 	SetExternal(key string, value interface{})
@@ -21,9 +24,44 @@ type CallConnector interface {
 	SetContactData(key string, value string)
 	GetSystem(key flow.SystemKey) *string
 	SetSystem(key flow.SystemKey, value string)
+	InvokeLexBot(name string, utterance string) (intent string, err error)
 	InvokeLambda(named string, inParams json.RawMessage, timeout time.Duration) (outJSON string, outErr error, err error)
 	GetFlowStart(flowName string) *flow.ModuleID
 	IsInHours(name string, isQueue bool) (bool, error)
+	SetEventHook(hook flow.EventHook, flowName string)
+	SetLogging(enabled bool)
+	SetRecording(agent bool, customer bool, analytics bool)
+	GetRoutingPriority() (priority int, age time.Duration)
+	SetRoutingPriority(priority int, age time.Duration)
+	Enqueue(queueARN string, queueName string) bool
+	GetQueueName(queueARN string) *string
+	GetQueueOutboundNumber(queueARN string) *string
+	GetHoursName(hoursARN string) *string
+	GetFlowName(flowARN string) *string
+	CreateTask(task Task) (contactID string, err error)
+	CreateCallback(callback Callback) (contactID string, err error)
+	NewContactID() string
+}
+
+// Task describes a task contact to be created by a Create Task block.
+type Task struct {
+	Name        string
+	Description string
+	FlowName    string
+	// References are links to be shown to the agent, keyed by name.
+	References map[string]string
+	// ScheduledTime is when the task should start. If zero, it starts straight away.
+	ScheduledTime time.Time
+}
+
+// Callback describes a callback contact to be created by a Create Callback block.
+type Callback struct {
+	// Tel is the number the customer is called back on.
+	Tel       string
+	QueueARN  string
+	QueueName string
+	// InitialDelay is how long the callback waits before it can be routed to an agent.
+	InitialDelay time.Duration
 }
 
 // Runner takes a call context and returns the ID of the next block to run, or nil if the call is over.
@@ -56,6 +94,26 @@ func MakeRunner(m flow.Module) Runner {
 		return checkHoursOfOperation(m)
 	case flow.ModuleSetVoice:
 		return setVoice(m)
+	case flow.ModuleSetEventHook:
+		return setEventHook(m)
+	case flow.ModuleSetLoggingBehavior:
+		return setLoggingBehavior(m)
+	case flow.ModuleSetRecordingBehavior:
+		return setRecordingBehavior(m)
+	case flow.ModuleChangeRoutingPriority:
+		return changeRoutingPriority(m)
+	case flow.ModuleCheckOutboundCallStatus:
+		return checkOutboundCallStatus(m)
+	case flow.ModuleWait:
+		return wait(m)
+	case flow.ModuleSendMessage:
+		return sendMessage(m)
+	case flow.ModuleCreateTask:
+		return createTask(m)
+	case flow.ModuleSetCallbackNumber:
+		return setCallbackNumber(m)
+	case flow.ModuleCreateCallback:
+		return createCallback(m)
 	default:
 		return passthrough(m)
 	}
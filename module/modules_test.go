@@ -12,19 +12,24 @@ import (
 )
 
 type testCallState struct {
-	i     string
-	o     string
-	oSSML bool
-	rcv   struct {
+	i              string
+	o              string
+	oSSML          bool
+	oInterruptible bool
+	oOptions       []string
+	rcv            struct {
 		count      int
 		timeout    time.Duration
 		encrypt    bool
-		terminator rune
+		interdigit time.Duration
+		terminator string
+		speech     bool
 		cert       []byte
 		keyID      string
 	}
-	encrypt  func(string, string, []byte) []byte
-	lambdaIn struct {
+	encrypt    func(string, string, []byte) []byte
+	encryptErr error
+	lambdaIn   struct {
 		name  string
 		input json.RawMessage
 	}
@@ -38,6 +43,29 @@ type testCallState struct {
 	events       []event.Event
 	inHours      func(string, bool, time.Time) (bool, error)
 	time         time.Time
+	hooks        map[flow.EventHook]string
+	logging      bool
+	recording    [3]bool
+	routing      struct {
+		priority int
+		age      time.Duration
+	}
+	queued      []string
+	queues      map[string]testQueue
+	hours       map[string]string
+	flows       map[string]string
+	capacity    int
+	tasks       []Task
+	taskErr     error
+	callbacks   []Callback
+	callbackErr error
+	contacts    int
+	lexBots     map[string]map[string]string
+}
+
+type testQueue struct {
+	name     string
+	outbound string
 }
 
 func (st testCallState) init() *testCallState {
@@ -53,6 +81,9 @@ func (st testCallState) init() *testCallState {
 	if st.flowStart == nil {
 		st.flowStart = map[string]flow.ModuleID{}
 	}
+	if st.hooks == nil {
+		st.hooks = map[flow.EventHook]string{}
+	}
 	st.events = make([]event.Event, 0)
 	return &st
 }
@@ -61,15 +92,39 @@ func (st *testCallState) Send(s string, ssml bool) {
 	st.o = s
 	st.oSSML = ssml
 }
-func (st *testCallState) Receive(count int, timeout time.Duration, terminator rune) (string, bool) {
+func (st *testCallState) SendInterruptible(s string, ssml bool) {
+	st.Send(s, ssml)
+	st.oInterruptible = true
+}
+func (st *testCallState) SendOptions(s string, ssml bool, options []string) {
+	st.SendInterruptible(s, ssml)
+	st.oOptions = options
+}
+func (st *testCallState) Receive(count int, timeout time.Duration, interdigit time.Duration, terminator string) (string, bool) {
 	st.rcv.count = count
 	st.rcv.timeout = timeout
+	st.rcv.interdigit = interdigit
 	st.rcv.terminator = terminator
 	if st.i == "" {
 		return "", false
 	}
 	return st.i, st.i != "Timeout"
 }
+func (st *testCallState) ReceiveSpeech(timeout time.Duration) (string, bool) {
+	st.rcv.timeout = timeout
+	st.rcv.speech = true
+	if st.i == "" {
+		return "", false
+	}
+	return st.i, st.i != "Timeout"
+}
+func (st *testCallState) InvokeLexBot(name string, utterance string) (string, error) {
+	intents, ok := st.lexBots[name]
+	if !ok {
+		return "", fmt.Errorf("unknown Lex bot: %s", name)
+	}
+	return intents[utterance], nil
+}
 func (st *testCallState) GetExternal(key string) *string {
 	val, found := st.external[key]
 	if !found {
@@ -121,8 +176,80 @@ func (st *testCallState) Emit(event event.Event) {
 func (st *testCallState) IsInHours(name string, isQueue bool) (bool, error) {
 	return st.inHours(name, isQueue, st.time)
 }
-func (st *testCallState) Encrypt(in string, keyID string, cert []byte) []byte {
-	return st.encrypt(in, keyID, cert)
+func (st *testCallState) Encrypt(in string, keyID string, cert []byte) ([]byte, error) {
+	if st.encryptErr != nil {
+		return nil, st.encryptErr
+	}
+	return st.encrypt(in, keyID, cert), nil
+}
+func (st *testCallState) SetEventHook(hook flow.EventHook, flowName string) {
+	st.hooks[hook] = flowName
+}
+func (st *testCallState) SetLogging(enabled bool) {
+	st.logging = enabled
+}
+func (st *testCallState) GetRoutingPriority() (int, time.Duration) {
+	return st.routing.priority, st.routing.age
+}
+func (st *testCallState) SetRoutingPriority(priority int, age time.Duration) {
+	st.routing.priority = priority
+	st.routing.age = age
+}
+func (st *testCallState) Enqueue(queueARN string, queueName string) bool {
+	if st.capacity > 0 && len(st.queued) >= st.capacity {
+		return false
+	}
+	st.queued = append(st.queued, queueName)
+	return true
+}
+func (st *testCallState) GetQueueName(queueARN string) *string {
+	q, ok := st.queues[queueARN]
+	if !ok {
+		return nil
+	}
+	return &q.name
+}
+func (st *testCallState) GetQueueOutboundNumber(queueARN string) *string {
+	q, ok := st.queues[queueARN]
+	if !ok || q.outbound == "" {
+		return nil
+	}
+	return &q.outbound
+}
+func (st *testCallState) GetHoursName(hoursARN string) *string {
+	name, ok := st.hours[hoursARN]
+	if !ok {
+		return nil
+	}
+	return &name
+}
+func (st *testCallState) GetFlowName(flowARN string) *string {
+	name, ok := st.flows[flowARN]
+	if !ok {
+		return nil
+	}
+	return &name
+}
+func (st *testCallState) CreateTask(task Task) (string, error) {
+	if st.taskErr != nil {
+		return "", st.taskErr
+	}
+	st.tasks = append(st.tasks, task)
+	return fmt.Sprintf("task-%d", len(st.tasks)), nil
+}
+func (st *testCallState) CreateCallback(cb Callback) (string, error) {
+	if st.callbackErr != nil {
+		return "", st.callbackErr
+	}
+	st.callbacks = append(st.callbacks, cb)
+	return st.NewContactID(), nil
+}
+func (st *testCallState) NewContactID() string {
+	st.contacts++
+	return fmt.Sprintf("contact-%d", st.contacts)
+}
+func (st *testCallState) SetRecording(agent bool, customer bool, analytics bool) {
+	st.recording = [3]bool{agent, customer, analytics}
 }
 
 func TestMakeRunner(t *testing.T) {
@@ -186,6 +313,56 @@ func TestMakeRunner(t *testing.T) {
 			module: `{ "type": "SetVoice" }`,
 			exp:    setVoice{},
 		},
+		{
+			desc:   "SetEventHook",
+			module: `{ "type": "SetEventHook" }`,
+			exp:    setEventHook{},
+		},
+		{
+			desc:   "SetLoggingBehavior",
+			module: `{ "type": "SetLoggingBehavior" }`,
+			exp:    setLoggingBehavior{},
+		},
+		{
+			desc:   "SetRecordingBehavior",
+			module: `{ "type": "SetRecordingBehavior" }`,
+			exp:    setRecordingBehavior{},
+		},
+		{
+			desc:   "ChangeRoutingPriority",
+			module: `{ "type": "ChangeRoutingPriority" }`,
+			exp:    changeRoutingPriority{},
+		},
+		{
+			desc:   "CheckOutboundCallStatus",
+			module: `{ "type": "CheckOutboundCallStatus" }`,
+			exp:    checkOutboundCallStatus{},
+		},
+		{
+			desc:   "Wait",
+			module: `{ "type": "Wait" }`,
+			exp:    wait{},
+		},
+		{
+			desc:   "SendMessage",
+			module: `{ "type": "SendMessage" }`,
+			exp:    sendMessage{},
+		},
+		{
+			desc:   "CreateTask",
+			module: `{ "type": "CreateTask" }`,
+			exp:    createTask{},
+		},
+		{
+			desc:   "SetCallBackNumber",
+			module: `{ "type": "SetCallBackNumber" }`,
+			exp:    setCallbackNumber{},
+		},
+		{
+			desc:   "CreateCallback",
+			module: `{ "type": "CreateCallback" }`,
+			exp:    createCallback{},
+		},
 		{
 			desc:   "Passthrough",
 			module: `{ "type": "WhatIsThisIDontEven" }`,
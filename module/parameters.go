@@ -30,7 +30,7 @@ func (call parameterResolver) get(namespace flow.ModuleParameterNamespace, key s
 	case flow.NamespaceSystem:
 		return call.GetSystem(flow.SystemKey(key)), nil
 	default:
-		return nil, fmt.Errorf("unknown namespace: %s", namespace)
+		return nil, newError(ErrInvalidParameter, "", "unknown namespace: %s", namespace)
 	}
 }
 
@@ -47,6 +47,10 @@ func (call parameterResolver) resolve(p flow.ModuleParameter) (val interface{},
 		if s, err = call.get(*p.Namespace, key); s != nil {
 			val = *s
 		}
+		var paramErr *Error
+		if errors.As(err, &paramErr) {
+			paramErr.Parameter = p.Name
+		}
 		if err != nil {
 			return
 		}
@@ -89,7 +93,7 @@ func (call parameterResolver) unmarshal(plist flow.ModuleParameterList, into int
 					continue
 				}
 				if !reflect.TypeOf(val).ConvertibleTo(sliceType) {
-					return fmt.Errorf("type mismatch in field %s. Cannot convert %s to %s", f.Name, reflect.TypeOf(val), sliceType)
+					return newError(ErrTypeMismatch, f.Name, "type mismatch in field %s. Cannot convert %s to %s", f.Name, reflect.TypeOf(val), sliceType)
 				}
 				vals.Index(j).Set(reflect.ValueOf(val).Convert(sliceType))
 				intov.Field(i).Set(vals)
@@ -108,14 +112,14 @@ func (call parameterResolver) unmarshal(plist flow.ModuleParameterList, into int
 			}
 			valv := reflect.ValueOf(val)
 			if !valv.Type().ConvertibleTo(f.Type.Elem()) {
-				return fmt.Errorf("type mismatch in field %s. Cannot convert %s to %s", f.Name, valv.Type(), f.Type.Elem())
+				return newError(ErrTypeMismatch, f.Name, "type mismatch in field %s. Cannot convert %s to %s", f.Name, valv.Type(), f.Type.Elem())
 			}
 			intov.Field(i).Set(reflect.New(f.Type.Elem()))
 			intov.Field(i).Elem().Set(valv.Convert(f.Type.Elem()))
 		default:
 			p, ok := plist.Get(f.Name)
 			if !ok {
-				return fmt.Errorf("missing parameter %s", f.Name)
+				return newError(ErrMissingParameter, f.Name, "missing parameter %s", f.Name)
 			}
 			val, err := call.resolve(p)
 			if err != nil {
@@ -126,7 +130,7 @@ func (call parameterResolver) unmarshal(plist flow.ModuleParameterList, into int
 			}
 			valv := reflect.ValueOf(val)
 			if !valv.Type().ConvertibleTo(f.Type) {
-				return fmt.Errorf("type mismatch in field %s. Cannot convert %s to %s", f.Name, valv.Type(), f.Type)
+				return newError(ErrTypeMismatch, f.Name, "type mismatch in field %s. Cannot convert %s to %s", f.Name, valv.Type(), f.Type)
 			}
 			intov.Field(i).Set(valv.Convert(f.Type))
 		}
@@ -134,6 +138,32 @@ func (call parameterResolver) unmarshal(plist flow.ModuleParameterList, into int
 	return nil
 }
 
+// ResolveParameters looks up the current value of each of a block's parameters, keyed by parameter name.
+// Key-value parameters (such as lambda inputs) are collected into a map under their shared name.
+// Parameters that cannot be resolved are left out.
+func ResolveParameters(plist flow.ModuleParameterList, call CallConnector) map[string]interface{} {
+	pr := parameterResolver{call}
+	out := map[string]interface{}{}
+	for _, p := range plist {
+		val, err := pr.resolve(p)
+		if err != nil || val == nil {
+			continue
+		}
+		kv, ok := val.(flow.KeyValue)
+		if !ok {
+			out[p.Name] = val
+			continue
+		}
+		m, ok := out[p.Name].(map[string]interface{})
+		if !ok {
+			m = map[string]interface{}{}
+			out[p.Name] = m
+		}
+		m[kv.K] = kv.V
+	}
+	return out
+}
+
 var jsonP = regexp.MustCompile(`\$\.([a-zA-Z]+)\.([0-9a-zA-Z_\-]+)`)
 
 // jsonPath takes a string like "you live in $.External.city" and interpolates the jsonPath components.
@@ -2,6 +2,7 @@ package module
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -10,10 +11,12 @@ import (
 
 func TestUnmarshalErrors(t *testing.T) {
 	testCases := []struct {
-		desc   string
-		params string
-		into   interface{}
-		expErr string
+		desc     string
+		params   string
+		into     interface{}
+		expErr   string
+		expKind  error
+		expParam string
 	}{
 		{
 			desc:   "not a pointer",
@@ -28,22 +31,28 @@ func TestUnmarshalErrors(t *testing.T) {
 			expErr: "second parameter should be non-nil pointer",
 		},
 		{
-			desc:   "single bad namespace",
-			params: `[{"name":"File","value":"bucket","namespace":"S3"}]`,
-			into:   &struct{ File string }{},
-			expErr: "unknown namespace: S3",
+			desc:     "single bad namespace",
+			params:   `[{"name":"File","value":"bucket","namespace":"S3"}]`,
+			into:     &struct{ File string }{},
+			expErr:   "unknown namespace: S3",
+			expKind:  ErrInvalidParameter,
+			expParam: "File",
 		},
 		{
-			desc:   "single missing parameter",
-			params: `[{"name":"File","value":"index.html"}]`,
-			into:   &struct{ Directory string }{},
-			expErr: "missing parameter Directory",
+			desc:     "single missing parameter",
+			params:   `[{"name":"File","value":"index.html"}]`,
+			into:     &struct{ Directory string }{},
+			expErr:   "missing parameter Directory",
+			expKind:  ErrMissingParameter,
+			expParam: "Directory",
 		},
 		{
-			desc:   "single type mismatch",
-			params: `[{"name":"File","value":"index.html"}]`,
-			into:   &struct{ File int }{},
-			expErr: "type mismatch in field File. Cannot convert string to int",
+			desc:     "single type mismatch",
+			params:   `[{"name":"File","value":"index.html"}]`,
+			into:     &struct{ File int }{},
+			expErr:   "type mismatch in field File. Cannot convert string to int",
+			expKind:  ErrTypeMismatch,
+			expParam: "File",
 		},
 		{
 			desc: "slice bad namespace",
@@ -51,8 +60,10 @@ func TestUnmarshalErrors(t *testing.T) {
 				{"name":"File","value":"bucket","namespace":"S3"},
 				{"name":"File","value":"index.html"}
 			]`,
-			into:   &struct{ File []string }{},
-			expErr: "unknown namespace: S3",
+			into:     &struct{ File []string }{},
+			expErr:   "unknown namespace: S3",
+			expKind:  ErrInvalidParameter,
+			expParam: "File",
 		},
 		{
 			desc: "slice type mismatch",
@@ -60,24 +71,30 @@ func TestUnmarshalErrors(t *testing.T) {
 				{"name":"Value","value":"5"},
 				{"name":"Value","value":10}
 			]`,
-			into:   &struct{ Value []string }{},
-			expErr: "type mismatch in field Value. Cannot convert float64 to string",
+			into:     &struct{ Value []string }{},
+			expErr:   "type mismatch in field Value. Cannot convert float64 to string",
+			expKind:  ErrTypeMismatch,
+			expParam: "Value",
 		},
 		{
 			desc: "pointer type mismatch",
 			params: `[
 				{"name":"Value","value":10}
 			]`,
-			into:   &struct{ Value *string }{},
-			expErr: "type mismatch in field Value. Cannot convert float64 to string",
+			into:     &struct{ Value *string }{},
+			expErr:   "type mismatch in field Value. Cannot convert float64 to string",
+			expKind:  ErrTypeMismatch,
+			expParam: "Value",
 		},
 		{
 			desc: "pointer bad namespace",
 			params: `[
 				{"name":"File","value":"bucket","namespace":"S3"}
 			]`,
-			into:   &struct{ File *string }{},
-			expErr: "unknown namespace: S3",
+			into:     &struct{ File *string }{},
+			expErr:   "unknown namespace: S3",
+			expKind:  ErrInvalidParameter,
+			expParam: "File",
 		},
 	}
 	for _, tC := range testCases {
@@ -95,6 +112,16 @@ func TestUnmarshalErrors(t *testing.T) {
 			if errStr != tC.expErr {
 				t.Errorf("expected error of '%v' but got '%v'", tC.expErr, errStr)
 			}
+			if tC.expKind == nil {
+				return
+			}
+			var blockErr *Error
+			if !errors.As(err, &blockErr) || !errors.Is(err, tC.expKind) {
+				t.Fatalf("expected error of kind '%v' but got %#v", tC.expKind, err)
+			}
+			if blockErr.Parameter != tC.expParam {
+				t.Errorf("expected error in parameter '%s' but got '%s'", tC.expParam, blockErr.Parameter)
+			}
 		})
 	}
 }
@@ -183,3 +210,43 @@ func TestUnmarshalOK(t *testing.T) {
 		t.Errorf("expected Parameters of %v but got %v", expParam, into.Parameter)
 	}
 }
+
+func TestResolveParameters(t *testing.T) {
+	testJSON := `{
+		"parameters":[
+			{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:lookup"},
+			{"name":"TimeLimit","value":"3"},
+			{"name":"Parameter","value":"testValue","key":"testKey1"},
+			{"name":"Parameter","value":"testValue2","key":"testKey2","namespace":"External"},
+			{"name":"Account","value":"accountNumber","namespace":"User Defined"},
+			{"name":"Missing","value":"missingKey","namespace":"User Defined"},
+			{"name":"BadNamespace","value":"bucket","namespace":"S3"}
+		]
+	}`
+	m := flow.Module{}
+	err := json.Unmarshal([]byte(testJSON), &m)
+	if err != nil {
+		t.Fatalf("error perparing parameters: %v", err)
+	}
+	c := testCallState{
+		external: map[string]string{
+			"testValue2": "foo",
+		},
+		contactData: map[string]string{
+			"accountNumber": "12345678",
+		},
+	}.init()
+	exp := map[string]interface{}{
+		"FunctionArn": "arn:aws:lambda:eu-west-2:456789012345:function:lookup",
+		"TimeLimit":   "3",
+		"Parameter": map[string]interface{}{
+			"testKey1": "testValue",
+			"testKey2": "foo",
+		},
+		"Account": "12345678",
+	}
+	got := ResolveParameters(m.Parameters, c)
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected parameters of %v but got %v", exp, got)
+	}
+}
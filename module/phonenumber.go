@@ -0,0 +1,133 @@
+package module
+
+import (
+	"errors"
+	"strings"
+)
+
+// phoneNumberMaxDigits is the most digits taken when a caller enters a phone number.
+const phoneNumberMaxDigits = 20
+
+// E.164 numbers have at most 15 digits, including the calling code.
+// Numbers shorter than the minimum are not dialable anywhere.
+const (
+	e164MaxDigits = 15
+	e164MinDigits = 7
+)
+
+// numberingPlan describes how local phone numbers are written in a country.
+type numberingPlan struct {
+	// code is the international calling code.
+	code string
+	// trunk is the prefix dialled before a number within the country, which is dropped from the international form.
+	trunk string
+}
+
+// numberingPlans are keyed by ISO 3166 country code. They are only needed to read numbers entered in local format.
+var numberingPlans = map[string]numberingPlan{
+	"AU": {code: "61", trunk: "0"},
+	"BR": {code: "55", trunk: "0"},
+	"CA": {code: "1", trunk: "1"},
+	"DE": {code: "49", trunk: "0"},
+	"ES": {code: "34"},
+	"FR": {code: "33", trunk: "0"},
+	"GB": {code: "44", trunk: "0"},
+	"HK": {code: "852"},
+	"IE": {code: "353", trunk: "0"},
+	"IN": {code: "91", trunk: "0"},
+	"IT": {code: "39"},
+	"JP": {code: "81", trunk: "0"},
+	"MX": {code: "52"},
+	"NL": {code: "31", trunk: "0"},
+	"NZ": {code: "64", trunk: "0"},
+	"PL": {code: "48"},
+	"SE": {code: "46", trunk: "0"},
+	"SG": {code: "65"},
+	"US": {code: "1", trunk: "1"},
+	"ZA": {code: "27", trunk: "0"},
+}
+
+// callingCodes are the country calling codes assigned by the ITU (E.164).
+var callingCodes = makeSet(`
+	1 7
+	20 27 30 31 32 33 34 36 39 40 41 43 44 45 46 47 48 49 51 52 53 54 55 56 57 58
+	60 61 62 63 64 65 66 81 82 84 86 90 91 92 93 94 95 98
+	211 212 213 216 218 220 221 222 223 224 225 226 227 228 229 230 231 232 233 234 235 236 237 238 239
+	240 241 242 243 244 245 246 247 248 249 250 251 252 253 254 255 256 257 258
+	260 261 262 263 264 265 266 267 268 269 290 291 297 298 299
+	350 351 352 353 354 355 356 357 358 359 370 371 372 373 374 375 376 377 378 379
+	380 381 382 383 385 386 387 388 389 420 421 423
+	500 501 502 503 504 505 506 507 508 509 590 591 592 593 594 595 596 597 598 599
+	670 672 673 674 675 676 677 678 679 680 681 682 683 685 686 687 688 689 690 691 692
+	800 808 850 852 853 855 856 870 878 880 881 882 883 886 888
+	960 961 962 963 964 965 966 967 968 970 971 972 973 974 975 976 977 979 992 993 994 995 996 998
+`)
+
+// nonGeographicCodes are calling codes for global services (eg. international freephone, satellite networks), which a callback cannot be made to.
+var nonGeographicCodes = makeSet(`800 808 870 878 881 882 883 888 979`)
+
+func makeSet(list string) map[string]bool {
+	set := map[string]bool{}
+	for _, s := range strings.Fields(list) {
+		set[s] = true
+	}
+	return set
+}
+
+// callingCode finds the calling code at the start of a number written without a leading +.
+// Calling codes are prefix-free, so at most one matches.
+func callingCode(number string) (string, bool) {
+	for n := 1; n <= 3 && n < len(number); n++ {
+		if callingCodes[number[:n]] {
+			return number[:n], true
+		}
+	}
+	return "", false
+}
+
+// errInvalidPhoneNumber is returned when entered digits are not a valid phone number.
+var errInvalidPhoneNumber = errors.New("invalid phone number")
+
+// formatE164 turns a phone number entered on the keypad into E.164 format (eg. +447878123456).
+// Local numbers are written as they would be dialled within the given country. A country without a known numbering plan gives an invalid number.
+// International numbers start with the calling code, optionally preceded by 00.
+func formatE164(entry string, format string, country string) (string, error) {
+	if entry == "" || strings.Trim(entry, "0123456789") != "" {
+		return "", errInvalidPhoneNumber
+	}
+	var number string
+	switch format {
+	case "Local":
+		plan, ok := numberingPlans[country]
+		if !ok {
+			return "", errInvalidPhoneNumber
+		}
+		number = plan.code + strings.TrimPrefix(entry, plan.trunk)
+	case "International":
+		number = strings.TrimPrefix(entry, "00")
+	default:
+		return "", newError(ErrInvalidParameter, "PhoneNumberFormat", "unknown phone number format: %s", format)
+	}
+	return parseE164("+" + number)
+}
+
+// parseE164 checks that a number is in E.164 format: a + then an assigned calling code, with no more than 15 digits in all.
+func parseE164(number string) (string, error) {
+	digits := strings.TrimPrefix(number, "+")
+	if digits == number || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", errInvalidPhoneNumber
+	}
+	if _, ok := callingCode(digits); !ok {
+		return "", errInvalidPhoneNumber
+	}
+	if len(digits) < e164MinDigits || len(digits) > e164MaxDigits {
+		return "", errInvalidPhoneNumber
+	}
+	return number, nil
+}
+
+// dialable reports whether a callback can be made to a number in E.164 format.
+func dialable(number string) bool {
+	code, ok := callingCode(strings.TrimPrefix(number, "+"))
+	return ok && !nonGeographicCodes[code]
+}
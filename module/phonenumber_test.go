@@ -0,0 +1,83 @@
+package module
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatE164(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		entry   string
+		format  string
+		country string
+		exp     string
+		expErr  string
+		expKind error
+	}{
+		{desc: "local GB mobile", entry: "07878123456", format: "Local", country: "GB", exp: "+447878123456"},
+		{desc: "local GB without trunk prefix", entry: "7878123456", format: "Local", country: "GB", exp: "+447878123456"},
+		{desc: "local US", entry: "2065550123", format: "Local", country: "US", exp: "+12065550123"},
+		{desc: "local US with trunk prefix", entry: "12065550123", format: "Local", country: "US", exp: "+12065550123"},
+		{desc: "local too short", entry: "01132", format: "Local", country: "GB", expErr: "invalid phone number"},
+		{desc: "local too long", entry: "0207123456789012", format: "Local", country: "GB", expErr: "invalid phone number"},
+		{desc: "local unknown country", entry: "07878123456", format: "Local", country: "XX", expErr: "invalid phone number"},
+		{desc: "international", entry: "447878123456", format: "International", exp: "+447878123456"},
+		{desc: "international with exit code", entry: "00447878123456", format: "International", exp: "+447878123456"},
+		{desc: "international three digit code", entry: "353861234567", format: "International", exp: "+353861234567"},
+		{desc: "international unknown code", entry: "999123456", format: "International", expErr: "invalid phone number"},
+		{desc: "international not in table", entry: "48123456789", format: "International", exp: "+48123456789"},
+		{desc: "international too short", entry: "447878", format: "International", expErr: "invalid phone number"},
+		{desc: "international too long", entry: "4478781234567890", format: "International", expErr: "invalid phone number"},
+		{desc: "international with extra zeros", entry: "0000447878123456", format: "International", expErr: "invalid phone number"},
+		{desc: "not digits", entry: "0787*123456", format: "Local", country: "GB", expErr: "invalid phone number"},
+		{desc: "empty", entry: "", format: "International", expErr: "invalid phone number"},
+		{desc: "unknown format", entry: "07878123456", format: "Galactic", expErr: "unknown phone number format: Galactic", expKind: ErrInvalidParameter},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := formatE164(tC.entry, tC.format, tC.country)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			if got != tC.exp {
+				t.Errorf("expected number of '%s' but got '%s'", tC.exp, got)
+			}
+			if tC.expKind != nil && !errors.Is(err, tC.expKind) {
+				t.Errorf("expected error of kind '%v' but got %#v", tC.expKind, err)
+			}
+		})
+	}
+}
+
+func TestParseE164(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		number string
+		exp    string
+	}{
+		{desc: "valid", number: "+447878123456", exp: "+447878123456"},
+		{desc: "country not in table", number: "+48123456789", exp: "+48123456789"},
+		{desc: "no plus", number: "447878123456"},
+		{desc: "exit code", number: "+00447878123456"},
+		{desc: "unassigned code", number: "+999123456789"},
+		{desc: "too long", number: "+4478781234567890"},
+		{desc: "not digits", number: "+44 7878 123456"},
+		{desc: "empty", number: ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := parseE164(tC.number)
+			if tC.exp == "" && err != errInvalidPhoneNumber {
+				t.Errorf("expected invalid phone number error but got %v", err)
+			}
+			if got != tC.exp {
+				t.Errorf("expected number of '%s' but got '%s'", tC.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type sendMessage flow.Module
+
+type sendMessageParams struct {
+	Text string
+}
+
+func (m sendMessage) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleSendMessage {
+		return nil, fmt.Errorf("module of type %s being run as sendMessage", m.Type)
+	}
+	if ch := call.GetSystem(flow.SystemChannel); ch == nil || *ch != flow.ChannelChat {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	pr := parameterResolver{call}
+	p := sendMessageParams{}
+	err = pr.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	call.Send(pr.jsonPath(p.Text), false)
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
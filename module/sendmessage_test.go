@@ -0,0 +1,82 @@
+package module
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+func TestSendMessage(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonOK := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SendMessage",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"Text","value":"Hi $.Attributes.name, how can we help?"}]
+	}`
+	testCases := []struct {
+		desc    string
+		module  string
+		channel string
+		exp     string
+		expErr  string
+		expOut  string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as sendMessage",
+		},
+		{
+			desc:    "voice call",
+			module:  jsonOK,
+			channel: flow.ChannelVoice,
+			exp:     "00000000-0000-4000-0000-000000000002",
+		},
+		{
+			desc:    "chat",
+			module:  jsonOK,
+			channel: flow.ChannelChat,
+			exp:     "00000000-0000-4000-0000-000000000001",
+			expOut:  "Hi Edward, how can we help?",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod sendMessage
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := testCallState{
+				system:      map[flow.SystemKey]string{flow.SystemChannel: tC.channel},
+				contactData: map[string]string{"name": "Edward"},
+			}.init()
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if state.o != tC.expOut {
+				t.Errorf("expected output of '%s' but got '%s'", tC.expOut, state.o)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type setCallbackNumber flow.Module
+
+type setCallbackNumberParams struct {
+	CallBackNumber string
+}
+
+func (m setCallbackNumber) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleSetCallbackNumber {
+		return nil, fmt.Errorf("module of type %s being run as setCallbackNumber", m.Type)
+	}
+	var p setCallbackNumberParams
+	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	// The number must already be in E.164 format, as Store Customer Input gives it.
+	tel, err := parseE164(p.CallBackNumber)
+	if err != nil {
+		return m.Branches.GetLink(flow.BranchInvalidPhoneNumber), nil
+	}
+	if !dialable(tel) {
+		return m.Branches.GetLink(flow.BranchNonDialableNumber), nil
+	}
+	call.SetSystem(flow.SystemCustomerCallback, tel)
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
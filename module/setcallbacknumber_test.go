@@ -0,0 +1,143 @@
+package module
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+func TestSetCallbackNumber(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonBadParam := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetCallBackNumber",
+		"parameters":[]
+	}`
+	jsonOK := `{
+		"id":"9155d755-b5fb-4c17-a560-8a9d1095eedf",
+		"type":"SetCallBackNumber",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"InvalidPhoneNumber","transition":"00000000-0000-4000-0000-000000000002"},
+			{"condition":"NonDialableNumber","transition":"00000000-0000-4000-0000-000000000003"}
+		],
+		"parameters":[{"name":"CallBackNumber","value":"Stored customer input","namespace":"System"}]
+	}`
+	testCases := []struct {
+		desc   string
+		module string
+		state  *testCallState
+		exp    string
+		expTel string
+		expErr string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as setCallbackNumber",
+		},
+		{
+			desc:   "missing parameter",
+			module: jsonBadParam,
+			expErr: "missing parameter CallBackNumber",
+		},
+		{
+			desc:   "success",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "+447878123456"},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expTel: "+447878123456",
+		},
+		{
+			desc:   "not in E.164 format",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "07878123456"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000002",
+		},
+		{
+			desc:   "too short",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "+447878"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000002",
+		},
+		{
+			desc:   "exit code",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "+00447878123456"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000002",
+		},
+		{
+			desc:   "country without local numbering plan",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "+37061234567"},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expTel: "+37061234567",
+		},
+		{
+			desc:   "international freephone",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "+80012345678"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000003",
+		},
+		{
+			desc:   "satellite network",
+			module: jsonOK,
+			state: testCallState{
+				system: map[flow.SystemKey]string{flow.SystemLastUserInput: "+8816123456789"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000003",
+		},
+		{
+			desc:   "no input",
+			module: jsonOK,
+			exp:    "00000000-0000-4000-0000-000000000002",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod setCallbackNumber
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if tel := state.system[flow.SystemCustomerCallback]; tel != tC.expTel {
+				t.Errorf("expected callback number of '%s' but got '%s'", tC.expTel, tel)
+			}
+		})
+	}
+}
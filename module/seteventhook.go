@@ -0,0 +1,28 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type setEventHook flow.Module
+
+func (m setEventHook) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleSetEventHook {
+		return nil, fmt.Errorf("module of type %s being run as setEventHook", m.Type)
+	}
+	t, ok := m.Parameters.Get("Type")
+	if _, isString := t.Value.(string); !ok || !isString {
+		return nil, newError(ErrMissingParameter, "Type", "missing Type parameter")
+	}
+	cfid, ok := m.Parameters.Get("ContactFlowId")
+	if !ok {
+		return nil, newError(ErrMissingParameter, "ContactFlowId", "missing ContactFlowId parameter")
+	}
+	if call.GetFlowStart(cfid.ResourceName) == nil {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	call.SetEventHook(flow.EventHook(t.Value.(string)), cfid.ResourceName)
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
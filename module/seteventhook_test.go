@@ -0,0 +1,123 @@
+package module
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+func TestSetEventHook(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonBadType := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetEventHook",
+		"parameters":[]
+	}`
+	jsonBadFlow := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetEventHook",
+		"parameters":[{"name":"Type","value":"CustomerRemaining"}]
+	}`
+	jsonOK := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetEventHook",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"Type","value":"CustomerRemaining"},
+			{
+				"name":"ContactFlowId",
+				"value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/ffffffff-0000-4000-0000-ffffffff0001",
+				"resourceName":"Survey"
+			}
+		]
+	}`
+	testCases := []struct {
+		desc     string
+		module   string
+		state    *testCallState
+		exp      string
+		expHooks map[flow.EventHook]string
+		expEvt   []event.Event
+		expErr   string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as setEventHook",
+		},
+		{
+			desc:   "missing type",
+			module: jsonBadType,
+			expErr: "missing Type parameter",
+		},
+		{
+			desc:   "missing flow",
+			module: jsonBadFlow,
+			expErr: "missing ContactFlowId parameter",
+		},
+		{
+			desc:     "unknown flow",
+			module:   jsonOK,
+			exp:      "00000000-0000-4000-0000-000000000002",
+			expHooks: map[flow.EventHook]string{},
+			expEvt:   []event.Event{},
+		},
+		{
+			desc:   "success",
+			module: jsonOK,
+			state: testCallState{
+				flowStart: map[string]flow.ModuleID{
+					"Survey": flow.ModuleID("00000000-0000-4000-0000-000000000003"),
+				},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expHooks: map[flow.EventHook]string{
+				flow.HookDisconnect: "Survey",
+			},
+			expEvt: []event.Event{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod setEventHook
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if tC.expHooks != nil && !reflect.DeepEqual(tC.expHooks, state.hooks) {
+				t.Errorf("expected hooks of '%v' but got '%v'", tC.expHooks, state.hooks)
+			}
+			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
+				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
+			}
+		})
+	}
+}
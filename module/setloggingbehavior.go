@@ -0,0 +1,33 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type setLoggingBehavior flow.Module
+
+type setLoggingBehaviorParams struct {
+	LoggingBehavior string
+}
+
+func (m setLoggingBehavior) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleSetLoggingBehavior {
+		return nil, fmt.Errorf("module of type %s being run as setLoggingBehavior", m.Type)
+	}
+	p := setLoggingBehaviorParams{}
+	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	switch p.LoggingBehavior {
+	case "Enable", "Enabled":
+		call.SetLogging(true)
+	case "Disable", "Disabled":
+		call.SetLogging(false)
+	default:
+		return nil, newError(ErrInvalidParameter, "LoggingBehavior", "invalid LoggingBehavior: %s", p.LoggingBehavior)
+	}
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
@@ -0,0 +1,112 @@
+package module
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+func TestSetLoggingBehavior(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonBadParam := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetLoggingBehavior",
+		"parameters":[]
+	}`
+	jsonBadValue := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetLoggingBehavior",
+		"parameters":[{"name":"LoggingBehavior","value":"Sometimes"}]
+	}`
+	jsonEnable := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetLoggingBehavior",
+		"branches":[{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"}],
+		"parameters":[{"name":"LoggingBehavior","value":"Enable"}]
+	}`
+	jsonDisable := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetLoggingBehavior",
+		"branches":[{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"}],
+		"parameters":[{"name":"LoggingBehavior","value":"Disable"}]
+	}`
+	testCases := []struct {
+		desc       string
+		module     string
+		state      *testCallState
+		exp        string
+		expLogging bool
+		expEvt     []event.Event
+		expErr     string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as setLoggingBehavior",
+		},
+		{
+			desc:   "missing parameter",
+			module: jsonBadParam,
+			expErr: "missing parameter LoggingBehavior",
+		},
+		{
+			desc:   "invalid parameter",
+			module: jsonBadValue,
+			expErr: "invalid LoggingBehavior: Sometimes",
+		},
+		{
+			desc:       "enable",
+			module:     jsonEnable,
+			exp:        "00000000-0000-4000-0000-000000000001",
+			expLogging: true,
+			expEvt:     []event.Event{},
+		},
+		{
+			desc:       "disable",
+			module:     jsonDisable,
+			state:      testCallState{logging: true}.init(),
+			exp:        "00000000-0000-4000-0000-000000000001",
+			expLogging: false,
+			expEvt:     []event.Event{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod setLoggingBehavior
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if state.logging != tC.expLogging {
+				t.Errorf("expected logging to be %v but it was %v", tC.expLogging, state.logging)
+			}
+			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
+				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package module
 
 import (
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
 )
@@ -13,11 +13,80 @@ func (m setQueue) Run(call CallConnector) (next *flow.ModuleID, err error) {
 	if m.Type != flow.ModuleSetQueue {
 		return nil, fmt.Errorf("module of type %s being run as setQueue", m.Type)
 	}
-	p, ok := m.Parameters.Get("Queue")
-	if !ok {
-		return nil, errors.New("missing Queue parameter")
+	pr := parameterResolver{call}
+	var arn, name string
+	if m.Target == flow.TargetAgent {
+		p, ok := m.Parameters.Get("Agent")
+		if !ok {
+			return nil, newError(ErrMissingParameter, "Agent", "missing Agent parameter")
+		}
+		val, err := pr.resolve(p)
+		if err != nil {
+			return nil, err
+		}
+		agentARN, _ := val.(string)
+		var agentID string
+		if arn, agentID, ok = agentQueueARN(agentARN); !ok {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		name = agentID
+		if n := call.GetQueueName(arn); n != nil {
+			name = *n
+		}
+		if isStatic(p) && p.ResourceName != "" {
+			name = p.ResourceName
+		}
+	} else {
+		p, ok := m.Parameters.Get("Queue")
+		if !ok {
+			return nil, newError(ErrMissingParameter, "Queue", "missing Queue parameter")
+		}
+		val, err := pr.resolve(p)
+		if err != nil {
+			return nil, err
+		}
+		if arn, _ = val.(string); arn == "" {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		if isStatic(p) {
+			name = p.ResourceName
+		}
+		if name == "" {
+			n := call.GetQueueName(arn)
+			if n == nil {
+				return m.Branches.GetLink(flow.BranchError), nil
+			}
+			name = *n
+		}
 	}
-	call.SetSystem(flow.SystemQueueARN, p.Value.(string))
-	call.SetSystem(flow.SystemQueueName, p.ResourceName)
+	call.SetSystem(flow.SystemQueueARN, arn)
+	call.SetSystem(flow.SystemQueueName, name)
+	outbound := ""
+	if n := call.GetQueueOutboundNumber(arn); n != nil {
+		outbound = *n
+	}
+	call.SetSystem(flow.SystemQueueOutboundNumber, outbound)
 	return m.Branches.GetLink(flow.BranchSuccess), nil
 }
+
+// isStatic returns true if the parameter holds its value directly, rather than naming a value to look up.
+func isStatic(p flow.ModuleParameter) bool {
+	return p.Namespace == nil || *p.Namespace == ""
+}
+
+// agentQueueARN takes the ARN of an agent and gives the ARN of that agent's personal queue, along with the agent's ID.
+// The ARN of an agent queue is also accepted as input.
+func agentQueueARN(arn string) (queueARN string, agentID string, ok bool) {
+	for _, infix := range []string{"/queue/agent/", "/agent/"} {
+		i := strings.LastIndex(arn, infix)
+		if i < 0 || !strings.HasPrefix(arn, "arn:") {
+			continue
+		}
+		agentID = arn[i+len(infix):]
+		if agentID == "" || strings.Contains(agentID, "/") {
+			return "", "", false
+		}
+		return arn[:i] + "/queue/agent/" + agentID, agentID, true
+	}
+	return "", "", false
+}
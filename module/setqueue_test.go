@@ -28,9 +28,56 @@ func TestSetQueue(t *testing.T) {
 		],
 		"parameters":[{"name":"Queue","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001","namespace":null,"resourceName":"Complaints"}]
 	}`
+	jsonDynamic := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetQueue",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"Queue","value":"queueArn","namespace":"External"}]
+	}`
+	jsonNotString := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetQueue",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"Queue","value":42}]
+	}`
+	jsonAgentBadParam := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetQueue",
+		"parameters":[],
+		"target":"Agent"
+	}`
+	jsonAgent := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetQueue",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"Agent","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/agent/aaaaaaaa-0000-4000-0000-000000000001","resourceName":"jsmith"}],
+		"target":"Agent"
+	}`
+	jsonAgentDynamic := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetQueue",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{"name":"Agent","value":"preferredAgent","namespace":"User Defined"}],
+		"target":"Agent"
+	}`
+	queueARN := "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0002"
+	agentQueue := "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/agent/aaaaaaaa-0000-4000-0000-000000000001"
 	testCases := []struct {
 		desc   string
 		module string
+		state  *testCallState
 		exp    string
 		expSys map[flow.SystemKey]string
 		expEvt []event.Event
@@ -51,11 +98,99 @@ func TestSetQueue(t *testing.T) {
 			module: jsonOK,
 			exp:    "00000000-0000-4000-0000-000000000001",
 			expSys: map[flow.SystemKey]string{
-				flow.SystemQueueARN:  "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
-				flow.SystemQueueName: "Complaints",
+				flow.SystemQueueARN:            "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
+				flow.SystemQueueName:           "Complaints",
+				flow.SystemQueueOutboundNumber: "",
 			},
 			expEvt: []event.Event{},
 		},
+		{
+			desc:   "dynamic - in catalogue",
+			module: jsonDynamic,
+			state: testCallState{
+				external: map[string]string{"queueArn": queueARN},
+				queues:   map[string]testQueue{queueARN: {name: "Billing", outbound: "+441234567890"}},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expSys: map[flow.SystemKey]string{
+				flow.SystemQueueARN:            queueARN,
+				flow.SystemQueueName:           "Billing",
+				flow.SystemQueueOutboundNumber: "+441234567890",
+			},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "dynamic - not in catalogue",
+			module: jsonDynamic,
+			state: testCallState{
+				external: map[string]string{"queueArn": queueARN},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "dynamic - not set",
+			module: jsonDynamic,
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "not a string",
+			module: jsonNotString,
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "agent - bad parameter",
+			module: jsonAgentBadParam,
+			expErr: "missing Agent parameter",
+		},
+		{
+			desc:   "agent",
+			module: jsonAgent,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expSys: map[flow.SystemKey]string{
+				flow.SystemQueueARN:  agentQueue,
+				flow.SystemQueueName: "jsmith",
+			},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "agent - dynamic",
+			module: jsonAgentDynamic,
+			state: testCallState{
+				contactData: map[string]string{"preferredAgent": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/agent/aaaaaaaa-0000-4000-0000-000000000001"},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expSys: map[flow.SystemKey]string{
+				flow.SystemQueueARN:  agentQueue,
+				flow.SystemQueueName: "aaaaaaaa-0000-4000-0000-000000000001",
+			},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "agent - dynamic - in catalogue",
+			module: jsonAgentDynamic,
+			state: testCallState{
+				contactData: map[string]string{"preferredAgent": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/agent/aaaaaaaa-0000-4000-0000-000000000001"},
+				queues:      map[string]testQueue{agentQueue: {name: "jsmith"}},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expSys: map[flow.SystemKey]string{
+				flow.SystemQueueARN:  agentQueue,
+				flow.SystemQueueName: "jsmith",
+			},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "agent - not an agent",
+			module: jsonAgentDynamic,
+			state: testCallState{
+				contactData: map[string]string{"preferredAgent": queueARN},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -64,7 +199,10 @@ func TestSetQueue(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error unmarshalling module: %v", err)
 			}
-			state := testCallState{}.init()
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
 			next, err := mod.Run(state)
 			errStr := ""
 			if err != nil {
@@ -0,0 +1,51 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type setRecordingBehavior flow.Module
+
+type setRecordingBehaviorParams struct {
+	RecordingBehaviorOption    *string
+	RecordingParticipantOption *string
+	AnalyticsBehaviorOption    *string
+}
+
+func (m setRecordingBehavior) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleSetRecordingBehavior {
+		return nil, fmt.Errorf("module of type %s being run as setRecordingBehavior", m.Type)
+	}
+	p := setRecordingBehaviorParams{}
+	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	var agent, customer, analytics bool
+	if p.RecordingBehaviorOption != nil && *p.RecordingBehaviorOption == "Enable" {
+		participants := "Both"
+		if p.RecordingParticipantOption != nil {
+			participants = *p.RecordingParticipantOption
+		}
+		switch participants {
+		case "Both":
+			agent, customer = true, true
+		case "Agent":
+			agent = true
+		case "Customer":
+			customer = true
+		default:
+			return nil, newError(ErrInvalidParameter, "RecordingParticipantOption", "invalid RecordingParticipantOption: %s", participants)
+		}
+	}
+	if p.AnalyticsBehaviorOption != nil && *p.AnalyticsBehaviorOption == "Enable" {
+		if !agent && !customer {
+			return nil, newError(ErrInvalidParameter, "AnalyticsBehaviorOption", "analytics can not be enabled without recording")
+		}
+		analytics = true
+	}
+	call.SetRecording(agent, customer, analytics)
+	return m.Branches.GetLink(flow.BranchSuccess), nil
+}
@@ -0,0 +1,167 @@
+package module
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+func TestSetRecordingBehavior(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonBadParticipant := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetRecordingBehavior",
+		"parameters":[
+			{"name":"RecordingBehaviorOption","value":"Enable"},
+			{"name":"RecordingParticipantOption","value":"Supervisor"}
+		]
+	}`
+	jsonBadAnalytics := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"SetRecordingBehavior",
+		"parameters":[
+			{"name":"RecordingBehaviorOption","value":"Disable"},
+			{"name":"AnalyticsBehaviorOption","value":"Enable"}
+		]
+	}`
+	jsonBoth := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetRecordingBehavior",
+		"branches":[{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"}],
+		"parameters":[
+			{"name":"RecordingBehaviorOption","value":"Enable"},
+			{"name":"RecordingParticipantOption","value":"Both"},
+			{"name":"AnalyticsBehaviorOption","value":"Enable"}
+		]
+	}`
+	jsonAgent := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetRecordingBehavior",
+		"branches":[{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"}],
+		"parameters":[
+			{"name":"RecordingBehaviorOption","value":"Enable"},
+			{"name":"RecordingParticipantOption","value":"Agent"}
+		]
+	}`
+	jsonCustomer := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetRecordingBehavior",
+		"branches":[{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"}],
+		"parameters":[
+			{"name":"RecordingBehaviorOption","value":"Enable"},
+			{"name":"RecordingParticipantOption","value":"Customer"},
+			{"name":"AnalyticsBehaviorOption","value":"Disable"}
+		]
+	}`
+	jsonOff := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"SetRecordingBehavior",
+		"branches":[{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"}],
+		"parameters":[
+			{"name":"RecordingBehaviorOption","value":"Disable"},
+			{"name":"RecordingParticipantOption","value":"Both"}
+		]
+	}`
+	testCases := []struct {
+		desc     string
+		module   string
+		exp      string
+		expRec   [3]bool
+		expEvt   []event.Event
+		expErr   string
+		expKind  error
+		expParam string
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as setRecordingBehavior",
+		},
+		{
+			desc:   "bad participant",
+			module: jsonBadParticipant,
+			expErr: "invalid RecordingParticipantOption: Supervisor",
+		},
+		{
+			desc:     "analytics without recording",
+			module:   jsonBadAnalytics,
+			expErr:   "analytics can not be enabled without recording",
+			expKind:  ErrInvalidParameter,
+			expParam: "AnalyticsBehaviorOption",
+		},
+		{
+			desc:   "agent and customer with analytics",
+			module: jsonBoth,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expRec: [3]bool{true, true, true},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "agent only",
+			module: jsonAgent,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expRec: [3]bool{true, false, false},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "customer only",
+			module: jsonCustomer,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expRec: [3]bool{false, true, false},
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "off",
+			module: jsonOff,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			expRec: [3]bool{false, false, false},
+			expEvt: []event.Event{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod setRecordingBehavior
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := testCallState{}.init()
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			if tC.expKind != nil {
+				var blockErr *Error
+				if !errors.As(err, &blockErr) || !errors.Is(err, tC.expKind) {
+					t.Fatalf("expected error of kind '%v' but got %#v", tC.expKind, err)
+				}
+				if blockErr.Parameter != tC.expParam {
+					t.Errorf("expected error in parameter '%s' but got '%s'", tC.expParam, blockErr.Parameter)
+				}
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if state.recording != tC.expRec {
+				t.Errorf("expected recording (agent, customer, analytics) of %v but got %v", tC.expRec, state.recording)
+			}
+			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
+				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
+			}
+		})
+	}
+}
@@ -2,26 +2,31 @@ package module
 
 import (
 	"encoding/base64"
-	"errors"
 	"fmt"
 	"strconv"
 	"time"
-	"unicode/utf8"
 
 	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
 )
 
 type storeUserInput flow.Module
 
+// defaultInterdigitTimeout is how long a caller can pause between key presses before their entry is taken as complete.
+const defaultInterdigitTimeout = 5 * time.Second
+
 type storeUserInputParams struct {
-	Text             string
-	Timeout          string
-	MaxDigits        int
-	TextToSpeechType string
-	EncryptEntry     bool
-	TerminatorDigits *string
-	EncryptionKeyId  *string
-	EncryptionKey    *string
+	Text              string
+	Timeout           string
+	MaxDigits         *int
+	TextToSpeechType  string
+	EncryptEntry      *bool
+	CustomerInputType *string
+	PhoneNumberFormat *string
+	CountryCode       *string
+	TerminatorDigits  *string
+	InterdigitTimeout *string
+	EncryptionKeyId   *string
+	EncryptionKey     *string
 }
 
 func (m storeUserInput) Run(call CallConnector) (next *flow.ModuleID, err error) {
@@ -36,27 +41,65 @@ func (m storeUserInput) Run(call CallConnector) (next *flow.ModuleID, err error)
 	}
 	timeout, err := strconv.Atoi(p.Timeout)
 	if err != nil {
-		return
+		return nil, newError(ErrInvalidParameter, "Timeout", "invalid Timeout: %s", p.Timeout)
+	}
+	interdigit := defaultInterdigitTimeout
+	if p.InterdigitTimeout != nil {
+		secs, err := strconv.Atoi(*p.InterdigitTimeout)
+		if err != nil {
+			return nil, newError(ErrInvalidParameter, "InterdigitTimeout", "invalid InterdigitTimeout: %s", *p.InterdigitTimeout)
+		}
+		interdigit = time.Duration(secs) * time.Second
+	}
+	phoneNumber := p.CustomerInputType != nil && *p.CustomerInputType == "PhoneNumber"
+	maxDigits := phoneNumberMaxDigits
+	if !phoneNumber {
+		if p.MaxDigits == nil {
+			return nil, newError(ErrMissingParameter, "MaxDigits", "missing parameter MaxDigits")
+		}
+		maxDigits = *p.MaxDigits
 	}
 	txt := pr.jsonPath(p.Text)
-	call.Send(txt, p.TextToSpeechType == "ssml")
-	terminator := '#'
+	call.SendInterruptible(txt, p.TextToSpeechType == "ssml")
+	terminator := "#"
 	if p.TerminatorDigits != nil {
-		terminator, _ = utf8.DecodeRuneInString(*p.TerminatorDigits)
+		terminator = *p.TerminatorDigits
 	}
-	entry, ok := call.Receive(p.MaxDigits, time.Duration(timeout)*time.Second, terminator)
+	entry, ok := call.Receive(maxDigits, time.Duration(timeout)*time.Second, interdigit, terminator)
 	if !ok {
 		call.SetSystem(flow.SystemLastUserInput, "Timeout")
 		return m.Branches.GetLink(flow.BranchSuccess), nil
 	}
-	if p.EncryptEntry {
+	if phoneNumber {
+		format, country := "International", ""
+		if p.PhoneNumberFormat != nil {
+			format = *p.PhoneNumberFormat
+		}
+		if p.CountryCode != nil {
+			country = *p.CountryCode
+		}
+		entry, err = formatE164(entry, format, country)
+		if err == errInvalidPhoneNumber {
+			if next = m.Branches.GetLink(flow.BranchInvalidNumber); next != nil {
+				return next, nil
+			}
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.EncryptEntry != nil && *p.EncryptEntry {
 		if p.EncryptionKey == nil {
-			return nil, errors.New("missing encryption key")
+			return nil, newError(ErrMissingParameter, "EncryptionKey", "missing encryption key")
 		}
 		if p.EncryptionKeyId == nil {
-			return nil, errors.New("missing encryption key ID")
+			return nil, newError(ErrMissingParameter, "EncryptionKeyId", "missing encryption key ID")
+		}
+		enc, err := call.Encrypt(entry, *p.EncryptionKeyId, []byte(*p.EncryptionKey))
+		if err != nil {
+			return nil, err
 		}
-		enc := call.Encrypt(entry, *p.EncryptionKeyId, []byte(*p.EncryptionKey))
 		entry = base64.StdEncoding.EncodeToString(enc)
 	}
 	call.SetSystem(flow.SystemLastUserInput, entry)
@@ -3,6 +3,7 @@ package module
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -35,6 +36,18 @@ func TestStoreUserInput(t *testing.T) {
 			{"name":"EncryptEntry","value":false}
 		]
 	}`
+	jsonBadInterdigit := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"StoreUserInput",
+		"branches":[],
+		"parameters":[
+			{"name":"Text","value":"prompt"},
+			{"name":"Timeout","value":"7"},
+			{"name":"MaxDigits","value":8},
+			{"name":"TextToSpeechType","value":"text"},
+			{"name":"InterdigitTimeout","value":"soon"}
+		]
+	}`
 	jsonOK := `{
 		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
 		"type":"StoreUserInput",
@@ -86,7 +99,77 @@ func TestStoreUserInput(t *testing.T) {
 			{"name":"MaxDigits","value":8},
 			{"name":"EncryptEntry","value":false},
 			{"name":"DisableCancel","value":false},
-			{"name":"TerminatorDigits","value":"0"}
+			{"name":"TerminatorDigits","value":"**"},
+			{"name":"InterdigitTimeout","value":"3"}
+		]
+	}`
+	jsonPhoneLocal := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"StoreUserInput",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"Text","value":"Please enter your phone number."},
+			{"name":"TextToSpeechType","value":"text"},
+			{"name":"CustomerInputType","value":"PhoneNumber"},
+			{"name":"PhoneNumberFormat","value":"Local"},
+			{"name":"CountryCode","value":"GB"},
+			{"name":"Timeout","value":"7"},
+			{"name":"EncryptEntry","value":false},
+			{"name":"DisableCancel","value":false}
+		]
+	}`
+	jsonPhoneInternational := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"StoreUserInput",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"Text","value":"Please enter your phone number, starting with the country code."},
+			{"name":"TextToSpeechType","value":"text"},
+			{"name":"CustomerInputType","value":"PhoneNumber"},
+			{"name":"PhoneNumberFormat","value":"International"},
+			{"name":"Timeout","value":"7"},
+			{"name":"EncryptEntry","value":false},
+			{"name":"DisableCancel","value":false}
+		]
+	}`
+	jsonPhoneInvalidBranch := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"StoreUserInput",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"InvalidNumber","transition":"00000000-0000-4000-0000-000000000003"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"Text","value":"Enter the number you would like to be called back at."},
+			{"name":"TextToSpeechType","value":"text"},
+			{"name":"CustomerInputType","value":"PhoneNumber"},
+			{"name":"Timeout","value":"6"},
+			{"name":"PhoneNumberFormat","value":"Local"},
+			{"name":"CountryCode","value":"US"}
+		]
+	}`
+	jsonPhoneBadCountry := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"StoreUserInput",
+		"branches":[
+			{"condition":"Success","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[
+			{"name":"Text","value":"Please enter your phone number."},
+			{"name":"TextToSpeechType","value":"text"},
+			{"name":"CustomerInputType","value":"PhoneNumber"},
+			{"name":"PhoneNumberFormat","value":"Local"},
+			{"name":"CountryCode","value":"XX"},
+			{"name":"Timeout","value":"7"},
+			{"name":"EncryptEntry","value":false}
 		]
 	}`
 	testCases := []struct {
@@ -96,10 +179,13 @@ func TestStoreUserInput(t *testing.T) {
 		exp              string
 		expPrompt        string
 		expErr           string
+		expKind          error
+		expParam         string
 		expSys           map[flow.SystemKey]string
 		expRcvTimeout    time.Duration
 		expRcvCount      int
-		expRcvTerminator rune
+		expRcvTerminator string
+		expRcvInterdigit time.Duration
 		expEvt           []event.Event
 	}{
 		{
@@ -113,9 +199,18 @@ func TestStoreUserInput(t *testing.T) {
 			expErr: "missing parameter Text",
 		},
 		{
-			desc:   "bad timeout parameter",
-			module: jsonBadTimeout,
-			expErr: `strconv.Atoi: parsing "fishcake": invalid syntax`,
+			desc:     "bad timeout parameter",
+			module:   jsonBadTimeout,
+			expErr:   "invalid Timeout: fishcake",
+			expKind:  ErrInvalidParameter,
+			expParam: "Timeout",
+		},
+		{
+			desc:     "bad interdigit timeout parameter",
+			module:   jsonBadInterdigit,
+			expErr:   "invalid InterdigitTimeout: soon",
+			expKind:  ErrInvalidParameter,
+			expParam: "InterdigitTimeout",
 		},
 		{
 			desc:   "timeout",
@@ -129,7 +224,8 @@ func TestStoreUserInput(t *testing.T) {
 			exp:              "00000000-0000-4000-0000-000000000001",
 			expEvt:           []event.Event{},
 			expPrompt:        "<speak>Please enter digits 1 and 3 of your passcode.</speak>",
-			expRcvTerminator: '#',
+			expRcvTerminator: "#",
+			expRcvInterdigit: 5 * time.Second,
 		},
 		{
 			desc:   "success",
@@ -149,7 +245,7 @@ func TestStoreUserInput(t *testing.T) {
 			expRcvCount:      8,
 			expRcvTimeout:    7 * time.Second,
 			expEvt:           []event.Event{},
-			expRcvTerminator: '#',
+			expRcvTerminator: "#",
 		},
 		{
 			desc:   "success - encrypted",
@@ -174,7 +270,18 @@ func TestStoreUserInput(t *testing.T) {
 			expRcvCount:      8,
 			expRcvTimeout:    7 * time.Second,
 			expEvt:           []event.Event{},
-			expRcvTerminator: '#',
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "encryption fails",
+			module: jsonOKEncrypted,
+			state: testCallState{
+				i:          "12345678",
+				encryptErr: errors.New("encryption key is not a PEM encoded certificate"),
+			}.init(),
+			expErr:           "encryption key is not a PEM encoded certificate",
+			expPrompt:        "hello",
+			expRcvTerminator: "#",
 		},
 		{
 			desc:   "success - custom terminator",
@@ -190,7 +297,108 @@ func TestStoreUserInput(t *testing.T) {
 			expRcvCount:      8,
 			expRcvTimeout:    7 * time.Second,
 			expEvt:           []event.Event{},
-			expRcvTerminator: '0',
+			expRcvTerminator: "**",
+			expRcvInterdigit: 3 * time.Second,
+		},
+		{
+			desc:   "phone number - local",
+			module: jsonPhoneLocal,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			state: testCallState{
+				i: "07878123456",
+			}.init(),
+			expSys: map[flow.SystemKey]string{
+				flow.SystemLastUserInput: "+447878123456",
+			},
+			expPrompt:        "Please enter your phone number.",
+			expRcvCount:      20,
+			expRcvTimeout:    7 * time.Second,
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "phone number - local - invalid",
+			module: jsonPhoneLocal,
+			exp:    "00000000-0000-4000-0000-000000000002",
+			state: testCallState{
+				i: "07878",
+			}.init(),
+			expPrompt:        "Please enter your phone number.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "phone number - international",
+			module: jsonPhoneInternational,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			state: testCallState{
+				i: "0012065550123",
+			}.init(),
+			expSys: map[flow.SystemKey]string{
+				flow.SystemLastUserInput: "+12065550123",
+			},
+			expPrompt:        "Please enter your phone number, starting with the country code.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "phone number - international - unknown country",
+			module: jsonPhoneInternational,
+			exp:    "00000000-0000-4000-0000-000000000002",
+			state: testCallState{
+				i: "999123456789",
+			}.init(),
+			expPrompt:        "Please enter your phone number, starting with the country code.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "phone number - timeout",
+			module: jsonPhoneLocal,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			state: testCallState{
+				i: "Timeout",
+			}.init(),
+			expSys: map[flow.SystemKey]string{
+				flow.SystemLastUserInput: "Timeout",
+			},
+			expPrompt:        "Please enter your phone number.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "phone number - invalid number branch",
+			module: jsonPhoneInvalidBranch,
+			exp:    "00000000-0000-4000-0000-000000000003",
+			state: testCallState{
+				i: "555",
+			}.init(),
+			expPrompt:        "Enter the number you would like to be called back at.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:   "phone number - invalid number branch - valid",
+			module: jsonPhoneInvalidBranch,
+			exp:    "00000000-0000-4000-0000-000000000001",
+			state: testCallState{
+				i: "2065550123",
+			}.init(),
+			expSys: map[flow.SystemKey]string{
+				flow.SystemLastUserInput: "+12065550123",
+			},
+			expPrompt:        "Enter the number you would like to be called back at.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
+		},
+		{
+			desc:             "phone number - bad country code",
+			module:           jsonPhoneBadCountry,
+			exp:              "00000000-0000-4000-0000-000000000002",
+			state:            testCallState{i: "07878123456"}.init(),
+			expPrompt:        "Please enter your phone number.",
+			expEvt:           []event.Event{},
+			expRcvTerminator: "#",
 		},
 	}
 	for _, tC := range testCases {
@@ -212,6 +420,15 @@ func TestStoreUserInput(t *testing.T) {
 			if errStr != tC.expErr {
 				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
 			}
+			if tC.expKind != nil {
+				var blockErr *Error
+				if !errors.As(err, &blockErr) || !errors.Is(err, tC.expKind) {
+					t.Fatalf("expected error of kind '%v' but got %#v", tC.expKind, err)
+				}
+				if blockErr.Parameter != tC.expParam {
+					t.Errorf("expected error in parameter '%s' but got '%s'", tC.expParam, blockErr.Parameter)
+				}
+			}
 			nextStr := ""
 			if next != nil {
 				nextStr = string(*next)
@@ -227,14 +444,20 @@ func TestStoreUserInput(t *testing.T) {
 			if state.o != tC.expPrompt {
 				t.Errorf("expected prompt of '%s' but got '%s'", tC.expPrompt, state.o)
 			}
+			if tC.expPrompt != "" && !state.oInterruptible {
+				t.Error("expected prompt to be interruptible but it was not")
+			}
 			if tC.expRcvCount > 0 && state.rcv.count != tC.expRcvCount {
 				t.Errorf("expected receive count of %d but got %d", tC.expRcvCount, state.rcv.count)
 			}
 			if tC.expRcvTimeout > 0 && state.rcv.timeout != tC.expRcvTimeout {
 				t.Errorf("expected receive timeout of %d but got %d", tC.expRcvTimeout, state.rcv.timeout)
 			}
+			if tC.expRcvInterdigit > 0 && state.rcv.interdigit != tC.expRcvInterdigit {
+				t.Errorf("expected receive interdigit timeout of %v but got %v", tC.expRcvInterdigit, state.rcv.interdigit)
+			}
 			if state.rcv.terminator != tC.expRcvTerminator {
-				t.Errorf("expected receive terminator of %s but got %s", string(tC.expRcvTerminator), string(state.rcv.terminator))
+				t.Errorf("expected receive terminator of %s but got %s", tC.expRcvTerminator, state.rcv.terminator)
 			}
 			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
 				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
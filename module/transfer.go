@@ -1,7 +1,6 @@
 package module
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/edwardbrowncross/amazon-connect-simulator/event"
@@ -18,13 +17,20 @@ func (m transfer) Run(call CallConnector) (next *flow.ModuleID, err error) {
 	case flow.TargetFlow:
 		cfid, ok := m.Parameters.Get("ContactFlowId")
 		if !ok {
-			return nil, errors.New("missing ContactFlowId parameter")
+			return nil, newError(ErrMissingParameter, "ContactFlowId", "missing ContactFlowId parameter")
 		}
-		next = call.GetFlowStart(cfid.ResourceName)
+		arn, name, err := resolveFlow(cfid, call)
+		if err != nil {
+			return nil, err
+		}
+		if name == nil {
+			return m.Branches.GetLink(flow.BranchError), nil
+		}
+		next = call.GetFlowStart(*name)
 		if next == nil {
 			return m.Branches.GetLink(flow.BranchError), nil
 		}
-		call.Emit(event.FlowTransferEvent{FlowARN: cfid.Value.(string), FlowName: cfid.ResourceName})
+		call.Emit(event.FlowTransferEvent{FlowARN: arn, FlowName: *name})
 		return next, nil
 	case flow.TargetQueue:
 		queue := call.GetSystem(flow.SystemQueueName)
@@ -32,23 +38,59 @@ func (m transfer) Run(call CallConnector) (next *flow.ModuleID, err error) {
 		if queue == nil || arn == nil {
 			return m.Branches.GetLink(flow.BranchError), nil
 		}
-		call.Emit(event.QueueTransferEvent{QueueARN: *arn, QueueName: *queue})
+		if !call.Enqueue(*arn, *queue) {
+			return m.Branches.GetLink(flow.BranchAtCapacity), nil
+		}
+		priority, age := call.GetRoutingPriority()
+		call.Emit(event.QueueTransferEvent{QueueARN: *arn, QueueName: *queue, Priority: priority, AgeAdjustment: age})
 		return nil, nil
 	case flow.TargetPhoneNumber:
 		blind, ok := m.Parameters.Get("BlindTransfer")
 		if _, isBool := blind.Value.(bool); !ok || !isBool {
-			return nil, errors.New("missing BlindTransfer parameter")
+			return nil, newError(ErrMissingParameter, "BlindTransfer", "missing BlindTransfer parameter")
 		}
 		num, ok := m.Parameters.Get("PhoneNumber")
 		if _, isString := num.Value.(string); !ok || !isString {
-			return nil, errors.New("missing PhoneNumber parameter")
+			return nil, newError(ErrMissingParameter, "PhoneNumber", "missing PhoneNumber parameter")
 		}
 		call.Emit(event.NumberTransferEvent{Tel: num.Value.(string)})
+		call.Emit(contactCreated(call, call.NewContactID(), "TRANSFER"))
 		if blind.Value.(bool) {
 			return nil, nil
 		}
 		return m.Branches.GetLink(flow.BranchSuccess), nil
 	default:
-		return nil, fmt.Errorf("unhandled transfer target: %s", m.Target)
+		return nil, newError(ErrUnknownTarget, "", "unhandled transfer target: %s", m.Target)
+	}
+}
+
+// resolveFlow gives the ARN and name of the flow in a ContactFlowId parameter.
+// Flows chosen in the block carry their name. Flows taken from an attribute are looked up by ARN. If the flow is unknown, name is nil.
+func resolveFlow(p flow.ModuleParameter, call CallConnector) (arn string, name *string, err error) {
+	val, err := parameterResolver{call}.resolve(p)
+	if err != nil {
+		return "", nil, err
+	}
+	arn, _ = val.(string)
+	if isStatic(p) && p.ResourceName != "" {
+		return arn, &p.ResourceName, nil
+	}
+	return arn, call.GetFlowName(arn), nil
+}
+
+// contactCreated describes a new voice contact that follows on from the call's contact, such as a callback or the leg to an external number.
+func contactCreated(call CallConnector, contactID string, initiationMethod string) event.ContactCreatedEvent {
+	evt := event.ContactCreatedEvent{
+		ContactID:        contactID,
+		InitiationMethod: initiationMethod,
+		Channel:          flow.ChannelVoice,
+	}
+	if id := call.GetSystem(flow.SystemContactID); id != nil {
+		evt.PreviousContactID = *id
+		evt.InitialContactID = *id
+	}
+	if id := call.GetSystem(flow.SystemInitialContactID); id != nil {
+		evt.InitialContactID = *id
 	}
+	return evt
 }
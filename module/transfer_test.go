@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edwardbrowncross/amazon-connect-simulator/event"
 	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
@@ -39,6 +40,20 @@ func TestTransfer(t *testing.T) {
 		}],
 		"target": "Flow"
 	}`
+	jsonFlowDynamic := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"Transfer",
+		"branches":[
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000002"}
+		],
+		"parameters":[{
+			"name":"ContactFlowId",
+			"value":"nextFlow",
+			"namespace":"User Defined"
+		}],
+		"target": "Flow"
+	}`
+	flowARN := "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/ffffffff-0000-4000-0000-ffffffff0001"
 	jsonQueueOK := `{
 		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
 		"type":"Transfer",
@@ -79,13 +94,14 @@ func TestTransfer(t *testing.T) {
 		"target": "PhoneNumber"
 	}`
 	testCases := []struct {
-		desc   string
-		module string
-		state  *testCallState
-		exp    string
-		expSys map[flow.SystemKey]string
-		expEvt []event.Event
-		expErr string
+		desc      string
+		module    string
+		state     *testCallState
+		exp       string
+		expSys    map[flow.SystemKey]string
+		expEvt    []event.Event
+		expQueued []string
+		expErr    string
 	}{
 		{
 			desc:   "wrong module",
@@ -136,16 +152,63 @@ func TestTransfer(t *testing.T) {
 		{
 			desc:   "success - queue",
 			module: jsonQueueOK,
+			state: func() *testCallState {
+				st := testCallState{
+					system: map[flow.SystemKey]string{
+						flow.SystemQueueName: "complaints",
+						flow.SystemQueueARN:  "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
+					},
+				}.init()
+				st.SetRoutingPriority(2, 30*time.Second)
+				return st
+			}(),
+			exp: "",
+			expEvt: []event.Event{
+				event.QueueTransferEvent{QueueName: "complaints", QueueARN: "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001", Priority: 2, AgeAdjustment: 30 * time.Second},
+			},
+			expQueued: []string{"complaints"},
+		},
+		{
+			desc:   "success - dynamic flow",
+			module: jsonFlowDynamic,
+			state: testCallState{
+				contactData: map[string]string{"nextFlow": flowARN},
+				flows:       map[string]string{flowARN: "Security"},
+				flowStart: map[string]flow.ModuleID{
+					"Security": "00000000-0000-4000-0000-000000000001",
+				},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
+			expEvt: []event.Event{
+				event.FlowTransferEvent{FlowName: "Security", FlowARN: flowARN},
+			},
+		},
+		{
+			desc:   "dynamic flow not in catalogue",
+			module: jsonFlowDynamic,
+			state: testCallState{
+				contactData: map[string]string{"nextFlow": flowARN},
+				flowStart: map[string]flow.ModuleID{
+					"Security": "00000000-0000-4000-0000-000000000001",
+				},
+			}.init(),
+			exp:    "00000000-0000-4000-0000-000000000002",
+			expEvt: []event.Event{},
+		},
+		{
+			desc:   "queue at capacity",
+			module: jsonQueueOK,
 			state: testCallState{
 				system: map[flow.SystemKey]string{
 					flow.SystemQueueName: "complaints",
 					flow.SystemQueueARN:  "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
 				},
+				queued:   []string{"complaints"},
+				capacity: 1,
 			}.init(),
-			exp: "",
-			expEvt: []event.Event{
-				event.QueueTransferEvent{QueueName: "complaints", QueueARN: "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001"},
-			},
+			exp:       "00000000-0000-4000-0000-000000000001",
+			expEvt:    []event.Event{},
+			expQueued: []string{"complaints"},
 		},
 		{
 			desc:   "success - blind number",
@@ -153,14 +216,28 @@ func TestTransfer(t *testing.T) {
 			exp:    "",
 			expEvt: []event.Event{
 				event.NumberTransferEvent{Tel: "+441234567890"},
+				event.ContactCreatedEvent{ContactID: "contact-1", InitiationMethod: "TRANSFER", Channel: "VOICE"},
 			},
 		},
 		{
 			desc:   "success - resumable number",
 			module: jsonNumberOK,
-			exp:    "00000000-0000-4000-0000-000000000001",
+			state: testCallState{
+				system: map[flow.SystemKey]string{
+					flow.SystemContactID:        "contact-0",
+					flow.SystemInitialContactID: "contact-0",
+				},
+			}.init(),
+			exp: "00000000-0000-4000-0000-000000000001",
 			expEvt: []event.Event{
 				event.NumberTransferEvent{Tel: "+441234567890"},
+				event.ContactCreatedEvent{
+					ContactID:         "contact-1",
+					PreviousContactID: "contact-0",
+					InitialContactID:  "contact-0",
+					InitiationMethod:  "TRANSFER",
+					Channel:           "VOICE",
+				},
 			},
 		},
 	}
@@ -198,6 +275,9 @@ func TestTransfer(t *testing.T) {
 			if (tC.expEvt != nil && !reflect.DeepEqual(tC.expEvt, state.events)) || (tC.expEvt == nil && len(state.events) > 0) {
 				t.Errorf("expected events of '%v' but got '%v'", tC.expEvt, state.events)
 			}
+			if !reflect.DeepEqual(tC.expQueued, state.queued) {
+				t.Errorf("expected queued contacts of '%v' but got '%v'", tC.expQueued, state.queued)
+			}
 		})
 	}
 }
@@ -0,0 +1,37 @@
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+type wait flow.Module
+
+type waitParams struct {
+	Timeout string
+}
+
+func (m wait) Run(call CallConnector) (next *flow.ModuleID, err error) {
+	if m.Type != flow.ModuleWait {
+		return nil, fmt.Errorf("module of type %s being run as wait", m.Type)
+	}
+	if ch := call.GetSystem(flow.SystemChannel); ch == nil || *ch != flow.ChannelChat {
+		return m.Branches.GetLink(flow.BranchError), nil
+	}
+	p := waitParams{}
+	err = parameterResolver{call}.unmarshal(m.Parameters, &p)
+	if err != nil {
+		return
+	}
+	tm, err := strconv.Atoi(p.Timeout)
+	if err != nil {
+		return nil, newError(ErrInvalidParameter, "Timeout", "invalid Timeout: %s", p.Timeout)
+	}
+	if _, ok := call.Receive(0, time.Duration(tm)*time.Second, 0, ""); !ok {
+		return m.Branches.GetLink(flow.BranchTimeout), nil
+	}
+	return m.Branches.GetLink(flow.BranchCustomerReturned), nil
+}
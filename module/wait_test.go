@@ -0,0 +1,104 @@
+package module
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+func TestWait(t *testing.T) {
+	jsonBadMod := `{
+		"id":"43dcc4f2-3392-4a38-90ed-0216f8594ea8",
+		"type":"Transfer"
+	}`
+	jsonBadTimeout := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"Wait",
+		"branches":[],
+		"parameters":[{"name":"Timeout","value":"soon"}]
+	}`
+	jsonOK := `{
+		"id":"55c7b51c-ab55-4c63-ac42-235b4a0f904f",
+		"type":"Wait",
+		"branches":[
+			{"condition":"CustomerReturned","transition":"00000000-0000-4000-0000-000000000001"},
+			{"condition":"Timeout","transition":"00000000-0000-4000-0000-000000000002"},
+			{"condition":"Error","transition":"00000000-0000-4000-0000-000000000003"}
+		],
+		"parameters":[{"name":"Timeout","value":"300"}]
+	}`
+	chat := map[flow.SystemKey]string{flow.SystemChannel: flow.ChannelChat}
+	testCases := []struct {
+		desc       string
+		module     string
+		state      *testCallState
+		exp        string
+		expErr     string
+		expTimeout time.Duration
+	}{
+		{
+			desc:   "wrong module",
+			module: jsonBadMod,
+			expErr: "module of type Transfer being run as wait",
+		},
+		{
+			desc:   "bad timeout",
+			module: jsonBadTimeout,
+			state:  testCallState{system: chat}.init(),
+			expErr: "invalid Timeout: soon",
+		},
+		{
+			desc:   "voice call",
+			module: jsonOK,
+			state:  testCallState{system: map[flow.SystemKey]string{flow.SystemChannel: flow.ChannelVoice}}.init(),
+			exp:    "00000000-0000-4000-0000-000000000003",
+		},
+		{
+			desc:       "customer returns",
+			module:     jsonOK,
+			state:      testCallState{system: chat, i: "I'm back"}.init(),
+			exp:        "00000000-0000-4000-0000-000000000001",
+			expTimeout: 300 * time.Second,
+		},
+		{
+			desc:       "timeout",
+			module:     jsonOK,
+			state:      testCallState{system: chat, i: "Timeout"}.init(),
+			exp:        "00000000-0000-4000-0000-000000000002",
+			expTimeout: 300 * time.Second,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var mod wait
+			err := json.Unmarshal([]byte(tC.module), &mod)
+			if err != nil {
+				t.Fatalf("unexpected error unmarshalling module: %v", err)
+			}
+			state := tC.state
+			if state == nil {
+				state = testCallState{}.init()
+			}
+			next, err := mod.Run(state)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tC.expErr {
+				t.Errorf("expected error of '%s' but got '%s'", tC.expErr, errStr)
+			}
+			nextStr := ""
+			if next != nil {
+				nextStr = string(*next)
+			}
+			if nextStr != tC.exp {
+				t.Errorf("expected next of '%s' but got '%s'", tC.exp, nextStr)
+			}
+			if state.rcv.timeout != tC.expTimeout {
+				t.Errorf("expected timeout of %v but got %v", tC.expTimeout, state.rcv.timeout)
+			}
+		})
+	}
+}
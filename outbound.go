@@ -0,0 +1,116 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+// OutboundOutcome is what happens when an outbound call rings the customer.
+// Values match the answering machine detection status recorded by Amazon Connect.
+type OutboundOutcome string
+
+// Ways an outbound call can be picked up (or not).
+const (
+	OutboundAnswered        OutboundOutcome = "HUMAN_ANSWERED"
+	OutboundVoicemailBeep                   = "VOICEMAIL_BEEP"
+	OutboundVoicemailNoBeep                 = "VOICEMAIL_NO_BEEP"
+	OutboundNoAnswer                        = "AMD_UNANSWERED"
+)
+
+// OutboundCallConfig describes an outbound call, in the manner of the StartOutboundVoiceContact API.
+type OutboundCallConfig struct {
+	// FlowName is the name of the flow run when the customer answers.
+	FlowName string
+	// DestNumber is the customer's phone number.
+	DestNumber string
+	// SourceNumber is the number the call appears to come from.
+	// It may be left empty if a queue with an outbound caller ID is given.
+	SourceNumber string
+	// Queue is the name or ARN of the queue the call is made from. It must be in the instance configuration.
+	// Its outbound caller ID is used if no source number is given, and its outbound whisper flow is run when the customer answers.
+	Queue string
+	// Attributes are contact attributes set before the flow starts.
+	Attributes map[string]string
+	// Outcome is what picks up the call. By default, the customer answers.
+	Outcome OutboundOutcome
+	// Time is the time the call is made (for in-hours check).
+	Time time.Time
+}
+
+// StartOutboundCall places a call to a customer and returns a Call object for interacting with that call.
+// InitiationMethod is OUTBOUND. If the call is answered, the queue's outbound whisper flow is run, followed by the given flow.
+// A Check Call Progress block in either flow can tell a person from voicemail.
+// If the outcome is OutboundNoAnswer, no flow is run and the call ends straight away.
+func (cs *Simulator) StartOutboundCall(config OutboundCallConfig) (*Call, error) {
+	if config.DestNumber == "" {
+		return nil, errors.New("a destination number must be provided in order to start an outbound call")
+	}
+	f, ok := cs.flows[config.FlowName]
+	if !ok {
+		return nil, fmt.Errorf("flow not found: %s. Load the flow with LoadFlow before calling this method", config.FlowName)
+	}
+	flows := []flow.ModuleID{f.Start}
+	var q *Queue
+	if config.Queue != "" {
+		found, ok := cs.instance.queueByARN(config.Queue)
+		if !ok {
+			found, ok = cs.instance.queueByName(config.Queue)
+		}
+		if !ok {
+			return nil, fmt.Errorf("queue not found: %s. Add the queue with LoadInstance before calling this method", config.Queue)
+		}
+		q = &found
+		if q.OutboundFlowID != "" {
+			whisper, ok := cs.outboundWhisperFlow(*q)
+			if !ok {
+				return nil, fmt.Errorf("outbound whisper flow %s for queue %s not loaded", q.OutboundFlowID, q.Name)
+			}
+			flows = []flow.ModuleID{whisper.Start, f.Start}
+		}
+	}
+	source := config.SourceNumber
+	if source == "" && q != nil {
+		source, _ = cs.instance.outboundNumber(*q)
+	}
+	if source == "" {
+		return nil, errors.New("a source number, or a queue with an outbound caller ID, must be provided in order to start an outbound call")
+	}
+	outcome := config.Outcome
+	if outcome == "" {
+		outcome = OutboundAnswered
+	}
+	if outcome == OutboundNoAnswer {
+		flows = nil
+	}
+
+	sc := &simulatorConnector{cs}
+	c := newCall(CallConfig{
+		SourceNumber: config.DestNumber,
+		DestNumber:   source,
+		Time:         config.Time,
+		Attributes:   config.Attributes,
+	}, sc)
+	c.System[flow.SystemInitiationMethod] = "OUTBOUND"
+	c.System[flow.SystemAnsweringMachineStatus] = string(outcome)
+	if q != nil {
+		c.System[flow.SystemQueueARN] = q.ARN
+		c.System[flow.SystemQueueName] = q.Name
+		n, _ := cs.instance.outboundNumber(*q)
+		c.System[flow.SystemQueueOutboundNumber] = n
+	}
+	c.start(sc, flows...)
+	return c, nil
+}
+
+// outboundWhisperFlow finds the loaded flow set as a queue's outbound whisper flow.
+func (cs *Simulator) outboundWhisperFlow(q Queue) (flow.Flow, bool) {
+	ref, ok := cs.instance.flows[q.OutboundFlowID]
+	if !ok {
+		return flow.Flow{}, false
+	}
+	f, ok := cs.flows[ref.Name]
+	return f, ok
+}
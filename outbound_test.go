@@ -0,0 +1,159 @@
+package simulator_test
+
+import (
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleOutbound = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0009-000000000001","type":"CheckOutboundCallStatus","branches":[{"condition":"CallAnswered","transition":"00000000-0000-4000-0009-000000000002"},{"condition":"VoicemailBeep","transition":"00000000-0000-4000-0009-000000000003"},{"condition":"VoicemailNoBeep","transition":"00000000-0000-4000-0009-000000000004"},{"condition":"NotDetected","transition":"00000000-0000-4000-0009-000000000004"},{"condition":"Error","transition":"00000000-0000-4000-0009-000000000004"}],"parameters":[]},
+        {"id":"00000000-0000-4000-0009-000000000002","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0009-000000000004"}],"parameters":[{"name":"Text","value":"This is a reminder of your appointment.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0009-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0009-000000000004"}],"parameters":[{"name":"Text","value":"Please call us back about your appointment.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0009-000000000004","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0009-000000000001",
+    "metadata":{"name":"Sample outbound flow","description":"","type":"contactFlow"}
+}`
+
+var sampleOutboundWhisper = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000a-000000000001","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000a-000000000002"}],"parameters":[{"name":"Text","value":"Hello from Example Sales.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000a-000000000002","type":"EndFlowExecution","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000a-000000000001",
+    "metadata":{"name":"Sample outbound whisper flow","description":"","type":"outboundWhisper"}
+}`
+
+var sampleOutboundQueue = `{
+    "Queue": {
+        "Name": "Reminders",
+        "QueueArn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0003",
+        "QueueId": "ffffffff-0000-4000-0000-ffffffff0003",
+        "OutboundCallerConfig": {
+            "OutboundCallerIdNumberId": "eeeeeeee-0000-4000-0000-eeeeeeee0001",
+            "OutboundFlowId": "bbbbbbbb-0000-4000-0000-bbbbbbbb0001"
+        }
+    }
+}`
+
+var sampleListContactFlows = `{
+    "ContactFlowSummaryList": [
+        {"Id": "bbbbbbbb-0000-4000-0000-bbbbbbbb0001", "Arn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/bbbbbbbb-0000-4000-0000-bbbbbbbb0001", "Name": "Sample outbound whisper flow", "ContactFlowType": "OUTBOUND_WHISPER"}
+    ]
+}`
+
+func TestStartOutboundCall(t *testing.T) {
+	sim := New()
+	for _, f := range []string{sampleOutbound, sampleOutboundWhisper} {
+		if err := sim.LoadFlowJSON([]byte(f)); err != nil {
+			t.Fatalf("unexpected error parsing flow: %v", err)
+		}
+	}
+	for _, j := range []string{sampleOutboundQueue, sampleListPhoneNumbers, sampleListContactFlows} {
+		if err := sim.LoadInstanceJSON([]byte(j)); err != nil {
+			t.Fatalf("unexpected error loading instance: %v", err)
+		}
+	}
+
+	t.Run("bad config", func(t *testing.T) {
+		testCases := []struct {
+			desc   string
+			config OutboundCallConfig
+			expErr string
+		}{
+			{
+				desc:   "no destination",
+				config: OutboundCallConfig{FlowName: "Sample outbound flow", SourceNumber: "+441121234567"},
+				expErr: "a destination number must be provided in order to start an outbound call",
+			},
+			{
+				desc:   "unknown flow",
+				config: OutboundCallConfig{FlowName: "Missing flow", DestNumber: "+447878000001", SourceNumber: "+441121234567"},
+				expErr: "flow not found: Missing flow. Load the flow with LoadFlow before calling this method",
+			},
+			{
+				desc:   "unknown queue",
+				config: OutboundCallConfig{FlowName: "Sample outbound flow", DestNumber: "+447878000001", Queue: "Missing"},
+				expErr: "queue not found: Missing. Add the queue with LoadInstance before calling this method",
+			},
+			{
+				desc:   "no source",
+				config: OutboundCallConfig{FlowName: "Sample outbound flow", DestNumber: "+447878000001"},
+				expErr: "a source number, or a queue with an outbound caller ID, must be provided in order to start an outbound call",
+			},
+		}
+		for _, tC := range testCases {
+			t.Run(tC.desc, func(t *testing.T) {
+				_, err := sim.StartOutboundCall(tC.config)
+				if err == nil || err.Error() != tC.expErr {
+					t.Errorf("expected error of '%s' but got '%v'", tC.expErr, err)
+				}
+			})
+		}
+	})
+	t.Run("answered", func(t *testing.T) {
+		call, err := sim.StartOutboundCall(OutboundCallConfig{
+			FlowName:     "Sample outbound flow",
+			DestNumber:   "+447878000001",
+			SourceNumber: "+441129876543",
+			Attributes:   map[string]string{"appointment": "2024-01-15T09:00"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("This is a reminder of your appointment.")
+		r := call.ContactRecord()
+		if r.InitiationMethod != "OUTBOUND" {
+			t.Errorf("expected initiation method of OUTBOUND but got %s", r.InitiationMethod)
+		}
+		if r.CustomerEndpoint != "+447878000001" || r.SystemEndpoint != "+441129876543" {
+			t.Errorf("expected customer +447878000001 and system +441129876543 but got %s and %s", r.CustomerEndpoint, r.SystemEndpoint)
+		}
+		if r.Attributes["appointment"] != "2024-01-15T09:00" {
+			t.Errorf("expected appointment attribute but got %v", r.Attributes)
+		}
+	})
+	t.Run("from queue with whisper", func(t *testing.T) {
+		call, err := sim.StartOutboundCall(OutboundCallConfig{
+			FlowName:   "Sample outbound flow",
+			DestNumber: "+447878000001",
+			Queue:      "Reminders",
+			Outcome:    OutboundVoicemailBeep,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("Hello from Example Sales.")
+		expect.Prompt().ToEqual("Please call us back about your appointment.")
+		r := call.ContactRecord()
+		if r.SystemEndpoint != "+441121234567" {
+			t.Errorf("expected queue's outbound caller ID as system endpoint but got %s", r.SystemEndpoint)
+		}
+		if r.AnsweringMachineDetectionStatus != "VOICEMAIL_BEEP" {
+			t.Errorf("expected answering machine status of VOICEMAIL_BEEP but got %s", r.AnsweringMachineDetectionStatus)
+		}
+	})
+	t.Run("no answer", func(t *testing.T) {
+		call, err := sim.StartOutboundCall(OutboundCallConfig{
+			FlowName:     "Sample outbound flow",
+			DestNumber:   "+447878000001",
+			SourceNumber: "+441129876543",
+			Outcome:      OutboundNoAnswer,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error starting call: %v", err)
+		}
+		for p := range call.Caller.O {
+			t.Errorf("expected no prompts but got '%s'", p)
+		}
+		if r := call.ContactRecord(); r.DisconnectReason != event.DisconnectOutboundAttemptFailed {
+			t.Errorf("expected disconnect reason of %s but got %s", event.DisconnectOutboundAttemptFailed, r.DisconnectReason)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package simulator
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultRoutingPriority is the priority given to contacts unless changed by a Change Routing Priority block.
+// Lower numbers are routed first.
+const defaultRoutingPriority = 5
+
+// QueuedContact is a contact that has been transferred to a queue and is waiting for an agent.
+type QueuedContact struct {
+	ContactID     string
+	QueueName     string
+	QueueARN      string
+	Priority      int
+	AgeAdjustment time.Duration
+	EnqueuedAt    time.Time
+	// InitiationMethod is how the contact came about: CALLBACK for a callback, otherwise that of the call that was queued.
+	InitiationMethod string
+	// PreviousContactID and InitialContactID link the contact to the contacts it follows on from.
+	PreviousContactID string
+	InitialContactID  string
+	// CustomerNumber is the customer's phone number. For a callback, it is the number they are called back on.
+	CustomerNumber string
+}
+
+// routedBefore returns true if this contact would be offered to an agent ahead of the other.
+// Contacts with a lower priority number go first. Within a priority, the contact that has waited longest goes first,
+// where the age adjustment counts as extra time spent waiting.
+func (q QueuedContact) routedBefore(other QueuedContact) bool {
+	if q.Priority != other.Priority {
+		return q.Priority < other.Priority
+	}
+	return q.EnqueuedAt.Add(-q.AgeAdjustment).Before(other.EnqueuedAt.Add(-other.AgeAdjustment))
+}
+
+// contactQueues holds the contacts waiting in each queue, in routing order.
+type contactQueues struct {
+	mutex   sync.Mutex
+	waiting map[string][]QueuedContact
+}
+
+func newContactQueues() *contactQueues {
+	return &contactQueues{
+		waiting: map[string][]QueuedContact{},
+	}
+}
+
+// add places a contact in its queue behind every contact that would be routed before it.
+// If max is more than zero and the queue already holds that many contacts, the contact is not added and false is returned.
+func (q *contactQueues) add(c QueuedContact, max int) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	waiting := q.waiting[c.QueueName]
+	if max > 0 && len(waiting) >= max {
+		return false
+	}
+	i := sort.Search(len(waiting), func(i int) bool {
+		return c.routedBefore(waiting[i])
+	})
+	waiting = append(waiting, QueuedContact{})
+	copy(waiting[i+1:], waiting[i:])
+	waiting[i] = c
+	q.waiting[c.QueueName] = waiting
+	return true
+}
+
+// list returns a copy of the contacts waiting in the named queue.
+func (q *contactQueues) list(queueName string) []QueuedContact {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return append([]QueuedContact{}, q.waiting[queueName]...)
+}
+
+// remove takes the contact with the given ID out of the named queue.
+// It returns false if the contact is not in the queue.
+func (q *contactQueues) remove(queueName string, contactID string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	waiting := q.waiting[queueName]
+	for i, c := range waiting {
+		if c.ContactID == contactID {
+			q.waiting[queueName] = append(waiting[:i:i], waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// pop removes and returns the contact at the front of the named queue.
+func (q *contactQueues) pop(queueName string) (QueuedContact, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	waiting := q.waiting[queueName]
+	if len(waiting) == 0 {
+		return QueuedContact{}, false
+	}
+	q.waiting[queueName] = waiting[1:]
+	return waiting[0], true
+}
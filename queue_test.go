@@ -0,0 +1,168 @@
+package simulator_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleRoutingPriority = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0005-000000000001","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0005-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0005-000000000003"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:vip-lookup"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0005-000000000002","type":"ChangeRoutingPriority","branches":[{"condition":"Success","transition":"00000000-0000-4000-0005-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0005-000000000003"}],"parameters":[{"name":"QueuePriority","value":"priority","namespace":"External"},{"name":"QueueTimeAdjustmentSeconds","value":"age","namespace":"External"}]},
+        {"id":"00000000-0000-4000-0005-000000000003","type":"SetQueue","branches":[{"condition":"Success","transition":"00000000-0000-4000-0005-000000000004"},{"condition":"Error","transition":"00000000-0000-4000-0005-000000000004"}],"parameters":[{"name":"Queue","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001","namespace":null,"resourceName":"Sales"}]},
+        {"id":"00000000-0000-4000-0005-000000000004","type":"Transfer","branches":[{"condition":"AtCapacity","transition":"00000000-0000-4000-0005-000000000005"},{"condition":"Error","transition":"00000000-0000-4000-0005-000000000005"}],"parameters":[],"target":"Queue"},
+        {"id":"00000000-0000-4000-0005-000000000005","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0005-000000000001",
+    "metadata":{"name":"Sample routing priority flow","description":"","type":"contactFlow"}
+}`
+
+func TestRoutingPriority(t *testing.T) {
+	sim := newTestSimulator(t, "Sample routing priority flow", sampleRoutingPriority)
+	ids := map[string]string{}
+	idsMutex := sync.Mutex{}
+	err := sim.RegisterLambda("vip-lookup", func(ctx context.Context, in LambdaPayload) (map[string]string, error) {
+		idsMutex.Lock()
+		ids[in.Details.ContactData.ContactID] = in.Details.ContactData.CustomerEndpoint.Address
+		idsMutex.Unlock()
+		switch in.Details.ContactData.CustomerEndpoint.Address {
+		case "+447878000001":
+			return map[string]string{"priority": "1", "age": "0"}, nil
+		case "+447878000002":
+			return map[string]string{"priority": "5", "age": "600"}, nil
+		default:
+			return map[string]string{"priority": "5", "age": "0"}, nil
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+
+	start := time.Date(2020, 06, 22, 13, 00, 0, 0, time.UTC)
+	calls := []struct {
+		tel      string
+		at       time.Time
+		priority int
+	}{
+		{"+447878000003", start, 5},
+		{"+447878000002", start.Add(5 * time.Minute), 5},
+		{"+447878000001", start.Add(10 * time.Minute), 1},
+		{"+447878000004", start.Add(time.Minute), 5},
+	}
+	for _, c := range calls {
+		call := startTestCall(t, sim, CallConfig{SourceNumber: c.tel, Time: c.at})
+		expect := flowtest.New(t, call)
+		expect.Transfer().WithPriority(c.priority).ToQueue("Sales")
+	}
+	idsMutex.Lock()
+	defer idsMutex.Unlock()
+
+	// VIP first, then the contact with ten minutes added to its age, then the others in the order they called.
+	exp := []string{"+447878000001", "+447878000002", "+447878000003", "+447878000004"}
+	queued := sim.QueuedContacts("Sales")
+	if len(queued) != len(exp) {
+		t.Fatalf("expected %d contacts in queue but got %d", len(exp), len(queued))
+	}
+	for i, q := range queued {
+		if ids[q.ContactID] != exp[i] {
+			t.Errorf("expected contact %d in queue to be %s but got %s", i, exp[i], ids[q.ContactID])
+		}
+	}
+	if q, ok := sim.DequeueContact("Sales"); !ok || ids[q.ContactID] != exp[0] {
+		t.Errorf("expected to dequeue %s but got %s", exp[0], ids[q.ContactID])
+	}
+	if n := len(sim.QueuedContacts("Sales")); n != len(exp)-1 {
+		t.Errorf("expected %d contacts to remain in queue but got %d", len(exp)-1, n)
+	}
+	if _, ok := sim.DequeueContact("Support"); ok {
+		t.Error("expected nothing to dequeue from an empty queue")
+	}
+}
+
+func TestAbandonedContacts(t *testing.T) {
+	sim := newTestSimulator(t, "Sample routing priority flow", sampleRoutingPriority)
+	sim.AddQueue(Queue{
+		Name:        "Sales",
+		ARN:         "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0001",
+		MaxContacts: 1,
+	})
+	err := sim.RegisterLambda("vip-lookup", func(ctx context.Context, in LambdaPayload) (map[string]string, error) {
+		return map[string]string{"priority": "5", "age": "0"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		abandon func(call *Call)
+	}{
+		{"caller hangs up", (*Call).Hangup},
+		{"call terminated", (*Call).Terminate},
+	}
+	for _, tC := range tests {
+		t.Run(tC.name, func(t *testing.T) {
+			call := startTestCall(t, sim, CallConfig{})
+			expect := flowtest.New(t, call)
+			expect.Transfer().ToQueue("Sales")
+			for range call.Caller.O {
+			}
+			if n := len(sim.QueuedContacts("Sales")); n != 1 {
+				t.Fatalf("expected 1 contact in queue but got %d", n)
+			}
+			tC.abandon(call)
+			if n := len(sim.QueuedContacts("Sales")); n != 0 {
+				t.Errorf("expected abandoned contact to leave the queue but %d remain", n)
+			}
+		})
+	}
+	if _, ok := sim.DequeueContact("Sales"); ok {
+		t.Error("expected nothing to dequeue after all callers left")
+	}
+}
+
+var sampleDynamicQueue = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0006-000000000001","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0006-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0006-000000000004"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:queue-lookup"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0006-000000000002","type":"SetQueue","branches":[{"condition":"Success","transition":"00000000-0000-4000-0006-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0006-000000000004"}],"parameters":[{"name":"Queue","value":"queueArn","namespace":"External"}]},
+        {"id":"00000000-0000-4000-0006-000000000003","type":"Transfer","branches":[{"condition":"AtCapacity","transition":"00000000-0000-4000-0006-000000000004"},{"condition":"Error","transition":"00000000-0000-4000-0006-000000000004"}],"parameters":[],"target":"Queue"},
+        {"id":"00000000-0000-4000-0006-000000000004","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0006-000000000005"}],"parameters":[{"name":"Text","value":"Sorry, something went wrong."},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0006-000000000005","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0006-000000000001",
+    "metadata":{"name":"Sample dynamic queue flow","description":"","type":"contactFlow"}
+}`
+
+func TestDynamicWorkingQueue(t *testing.T) {
+	sim := newTestSimulator(t, "Sample dynamic queue flow", sampleDynamicQueue)
+	sim.AddQueue(Queue{
+		Name: "Billing",
+		ARN:  "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0002",
+	})
+	err := sim.RegisterLambda("queue-lookup", func(ctx context.Context, in LambdaPayload) (map[string]string, error) {
+		if in.Details.ContactData.CustomerEndpoint.Address == "+447878000001" {
+			return map[string]string{"queueArn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0002"}, nil
+		}
+		return map[string]string{"queueArn": "arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0404"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+
+	t.Run("known queue", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{SourceNumber: "+447878000001"})
+		expect := flowtest.New(t, call)
+		expect.Transfer().ToQueue("Billing")
+	})
+	t.Run("unknown queue", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{SourceNumber: "+447878000002"})
+		expect := flowtest.New(t, call)
+		expect.Transfer().Never().ToQueue("Billing")
+		expect.Prompt().ToEqual("Sorry, something went wrong.")
+	})
+}
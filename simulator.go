@@ -1,9 +1,11 @@
 package simulator
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -13,23 +15,39 @@ import (
 // Simulator is capable of starting new simulated call flows.
 type Simulator struct {
 	lambdas   map[string]interface{}
+	lexBots   map[string]func(utterance string) (intent string)
 	flows     map[string]flow.Flow
 	modules   map[flow.ModuleID]flow.Module
-	encrypt   func(string, string, []byte) []byte
+	modFlow   map[flow.ModuleID]string
+	encrypt   func(string, string, []byte) ([]byte, error)
 	isInHours func(string, bool, time.Time) (bool, error)
 	telFlow   map[string]flow.Flow
+	flowLog   *flowLogger
+	queues    *contactQueues
+	instance  *instanceModel
+	guards    LoopGuards
+	clock     func() time.Time
+	newID     func() string
+	// onTaskCreated is called with each task created by a flow.
+	onTaskCreated func(task *Call)
 }
 
 // New creates a new call simulator.
 // It is created blank and must be set up using its attached methods.
 func New() Simulator {
 	return Simulator{
-		lambdas:   map[string]interface{}{},
-		flows:     map[string]flow.Flow{},
-		modules:   map[flow.ModuleID]flow.Module{},
-		telFlow:   map[string]flow.Flow{},
-		encrypt:   func(in string, keyID string, cert []byte) []byte { return []byte(in) },
-		isInHours: func(string, bool, time.Time) (bool, error) { return true, nil },
+		lambdas:  map[string]interface{}{},
+		lexBots:  map[string]func(string) string{},
+		flows:    map[string]flow.Flow{},
+		modules:  map[flow.ModuleID]flow.Module{},
+		modFlow:  map[flow.ModuleID]string{},
+		telFlow:  map[string]flow.Flow{},
+		queues:   newContactQueues(),
+		instance: newInstanceModel(),
+		guards:   DefaultLoopGuards,
+		clock:    time.Now,
+		newID:    randomID,
+		encrypt:  func(in string, keyID string, cert []byte) ([]byte, error) { return []byte(in), nil },
 	}
 }
 
@@ -40,6 +58,7 @@ func (cs *Simulator) LoadFlow(f flow.Flow) {
 	cs.flows[f.Metadata.Name] = f
 	for _, m := range f.Modules {
 		cs.modules[m.ID] = m
+		cs.modFlow[m.ID] = f.Metadata.Name
 	}
 }
 
@@ -79,6 +98,13 @@ func (cs *Simulator) RegisterLambda(name string, fn interface{}) error {
 	return nil
 }
 
+// RegisterLexBot specifies how speech sent to a Lex bot is understood.
+// name is the name of the bot. fn is given what the caller said and returns the name of the intent it matches.
+// A Get Customer Input block using a bot that has not been registered takes its error branch.
+func (cs *Simulator) RegisterLexBot(name string, fn func(utterance string) (intent string)) {
+	cs.lexBots[name] = fn
+}
+
 // SetStartingFlowFor specifies the name of the flow that should be run when a new call comes in to a given number.
 // The telephone number should match what will be used when creating a call.
 // The name is the full name given to the flow in the Amazon Connect ui.
@@ -93,23 +119,59 @@ func (cs *Simulator) SetStartingFlowFor(tel string, flowName string) error {
 }
 
 // SetEncryption defines how encryption is performed when encryption is enable in a Store Customer Input block.
-// No encryption is currently supplied by this simulator. By default, the string is no encrypted.
+// By default, the string is not encrypted. To encrypt as Amazon Connect does, use SetCertificateEncryption.
 // You may supply a function that takes the input digits and returns a cipher string. This may be real encryption or a dummy process.
 func (cs *Simulator) SetEncryption(encryptor func(in string, keyID string, cert []byte) (encrypted []byte)) {
-	cs.encrypt = encryptor
+	cs.encrypt = func(in string, keyID string, cert []byte) ([]byte, error) {
+		return encryptor(in, keyID, cert), nil
+	}
 }
 
 // SetInHoursCheck adds logic used by the checkHoursOfOperation block to determine if we are in operating hours.
 // The first parameter of the provided function will either be the name of a Queue or the name of an Hours of Operation, as indicated by the second parameter.
 // It should return true if we are in operating hours and false if not.
 // Returning an error indicates that the given queue/hours does not exist or does not have hours defined. The call will proceed down the error path.
+// Without this, the hours of operation in the instance configuration are used (see LoadInstance).
 func (cs *Simulator) SetInHoursCheck(checker func(name string, isQueue bool, time time.Time) (inOperation bool, err error)) {
 	cs.isInHours = checker
 }
 
+// SetFlowLog sets where contact flow logs are written.
+// Log entries are only written for calls that have turned on logging with a Set Logging Behavior block.
+// Each entry is written as a line of JSON in the format used by Amazon Connect in CloudWatch (see FlowLogEntry).
+// Any io.Writer may be used, including an *os.File. Entries from all calls started by this simulator go to the same writer.
+// If an entry cannot be written, the call ends with the write error in its Err field.
+func (cs *Simulator) SetFlowLog(w io.Writer) {
+	if w == nil {
+		cs.flowLog = nil
+		return
+	}
+	cs.flowLog = &flowLogger{w: w}
+}
+
+// QueuedContacts lists the contacts that have been transferred to the named queue and are waiting for an agent.
+// They are listed in the order they would be routed to agents: by routing priority, then by age.
+func (cs *Simulator) QueuedContacts(queueName string) []QueuedContact {
+	return cs.queues.list(queueName)
+}
+
+// DequeueContact removes the contact at the front of the named queue, as if it had been answered by an agent.
+// Contacts whose callers hang up or are terminated leave the queue by themselves.
+// If the queue is empty, ok is false.
+func (cs *Simulator) DequeueContact(queueName string) (contact QueuedContact, ok bool) {
+	return cs.queues.pop(queueName)
+}
+
 // StartCall starts a new call asynchronously and returns a Call object for interacting with that call.
 // Many independent calls can be spawned from one simulator.
 func (cs *Simulator) StartCall(config CallConfig) (*Call, error) {
+	return cs.StartCallContext(context.Background(), config)
+}
+
+// StartCallContext starts a new call, as StartCall does, that is bound to the given context.
+// If the context is cancelled or passes its deadline before the call ends, the call is terminated and its Err is set to the context's error.
+// Lambdas invoked by the call are given a context derived from it.
+func (cs *Simulator) StartCallContext(ctx context.Context, config CallConfig) (*Call, error) {
 	if config.DestNumber == "" {
 		return nil, errors.New("a destination number must be provided in order to start a flow")
 	}
@@ -117,7 +179,14 @@ func (cs *Simulator) StartCall(config CallConfig) (*Call, error) {
 	if !ok {
 		return nil, errors.New("no starting flow set. Call SetStartingFlowFor before starting a call")
 	}
-	return newCall(config, &simulatorConnector{cs}, start.Start), nil
+	if config.Channel != "" && config.Channel != flow.ChannelVoice && config.Channel != flow.ChannelChat {
+		return nil, fmt.Errorf("calls must be on the %s or %s channel but got %s. Use StartTask to start a task", flow.ChannelVoice, flow.ChannelChat, config.Channel)
+	}
+	sc := &simulatorConnector{cs}
+	c := newCall(config, sc)
+	c.ctx = ctx
+	c.start(sc, start.Start)
+	return c, nil
 }
 
 // simulatorConnector exposes methods for modules to get information from the base simulator.
@@ -144,6 +213,11 @@ func (cs *simulatorConnector) GetFlowStart(flowName string) *flow.ModuleID {
 	return &f.Start
 }
 
+// GetModuleFlowName gets the name of the flow that the block with the given ID belongs to.
+func (cs *simulatorConnector) GetModuleFlowName(moduleID flow.ModuleID) string {
+	return cs.modFlow[moduleID]
+}
+
 // GetModule finds the block with the given ID.
 func (cs *simulatorConnector) GetModule(moduleID flow.ModuleID) *flow.Module {
 	m, ok := cs.modules[moduleID]
@@ -153,18 +227,92 @@ func (cs *simulatorConnector) GetModule(moduleID flow.ModuleID) *flow.Module {
 	return &m
 }
 
-func (cs *simulatorConnector) Encrypt(in string, keyID string, cert []byte) []byte {
+// GetQueueName gets the name of the queue with the given ARN from the instance configuration.
+func (cs *simulatorConnector) GetQueueName(queueARN string) *string {
+	q, ok := cs.instance.queueByARN(queueARN)
+	if !ok || q.Name == "" {
+		return nil
+	}
+	return &q.Name
+}
+
+// GetQueueOutboundNumber gets the caller ID used for outbound calls from the queue with the given ARN.
+func (cs *simulatorConnector) GetQueueOutboundNumber(queueARN string) *string {
+	q, ok := cs.instance.queueByARN(queueARN)
+	if !ok {
+		return nil
+	}
+	n, ok := cs.instance.outboundNumber(q)
+	if !ok {
+		return nil
+	}
+	return &n
+}
+
+// GetHoursName gets the name of the hours of operation with the given ARN from the instance configuration.
+func (cs *simulatorConnector) GetHoursName(hoursARN string) *string {
+	h, ok := cs.instance.hoursByARN(hoursARN)
+	if !ok || h.Name == "" {
+		return nil
+	}
+	return &h.Name
+}
+
+// GetFlowName gets the name of the flow with the given ARN from the instance configuration.
+func (cs *simulatorConnector) GetFlowName(flowARN string) *string {
+	f, ok := cs.instance.flowByARN(flowARN)
+	if !ok || f.Name == "" {
+		return nil
+	}
+	return &f.Name
+}
+
+// queueCapacity gets the most contacts that may wait in the queue with the given ARN, or zero if there is no limit.
+func (cs *simulatorConnector) queueCapacity(queueARN string) int {
+	q, ok := cs.instance.queueByARN(queueARN)
+	if !ok {
+		return 0
+	}
+	return q.MaxContacts
+}
+
+// InvokeLexBot passes what the caller said to a registered Lex bot.
+func (cs *simulatorConnector) InvokeLexBot(name string, utterance string) (string, error) {
+	fn, ok := cs.lexBots[name]
+	if !ok {
+		return "", fmt.Errorf("unknown Lex bot: %s", name)
+	}
+	return fn(utterance), nil
+}
+
+func (cs *simulatorConnector) Encrypt(in string, keyID string, cert []byte) ([]byte, error) {
 	return cs.encrypt(in, keyID, cert)
 }
 
-func (cs *simulatorConnector) InvokeLambda(named string, withJSON string) (outJSON string, outErr error, err error) {
+func (cs *simulatorConnector) InvokeLambda(ctx context.Context, named string, withJSON string) (outJSON string, outErr error, err error) {
 	fn := cs.GetLambda(named)
 	if fn == nil {
 		return "", nil, fmt.Errorf("unknown lambda: %s", named)
 	}
-	return invokeLambda(fn, withJSON)
+	return invokeLambda(ctx, fn, withJSON)
 }
 
+func (cs *simulatorConnector) WriteFlowLog(e FlowLogEntry) error {
+	if cs.flowLog == nil {
+		return nil
+	}
+	return cs.flowLog.write(e)
+}
+
+// IsInHours checks opening hours with the function given to SetInHoursCheck if there is one.
+// Otherwise, hours of operation from the instance configuration are used. Queues and hours that are not configured are always open.
 func (cs *simulatorConnector) IsInHours(name string, isQueue bool, time time.Time) (bool, error) {
-	return cs.isInHours(name, isQueue, time)
+	if cs.isInHours != nil {
+		return cs.isInHours(name, isQueue, time)
+	}
+	open, known, err := cs.instance.isInHours(name, isQueue, time)
+	if !known {
+		return true, nil
+	}
+	return open, err
 }
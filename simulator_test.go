@@ -10,6 +10,7 @@ import (
 	"time"
 
 	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
 	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
 	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
 )
@@ -402,3 +403,112 @@ func TestSimulator(t *testing.T) {
 	}
 	t.Logf("\nFlow coverage: %.0f%%", coverage.Coverage()*100)
 }
+
+var sampleHangupMain = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0001-000000000001","type":"SetEventHook","branches":[{"condition":"Success","transition":"00000000-0000-4000-0001-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0001-000000000002"}],"parameters":[{"name":"Type","value":"CustomerRemaining"},{"name":"ContactFlowId","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/00000000-0000-4000-0002-000000000000","resourceName":"Sample disconnect flow"}]},
+        {"id":"00000000-0000-4000-0001-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0001-000000000003"},{"condition":"Timeout","transition":"00000000-0000-4000-0001-000000000003"},{"condition":"NoMatch","transition":"00000000-0000-4000-0001-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0001-000000000003"}],"parameters":[{"name":"Text","value":"Press 1 to finish."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}]},
+        {"id":"00000000-0000-4000-0001-000000000003","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0001-000000000001",
+    "metadata":{"name":"Sample hangup flow","description":"","type":"contactFlow"}
+}`
+
+var sampleHangupDisconnect = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0002-000000000001","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0002-000000000002"}],"parameters":[{"name":"Text","value":"Nobody will hear this."},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0002-000000000002","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0002-000000000003"},{"condition":"Error","transition":"00000000-0000-4000-0002-000000000003"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:clean-up"},{"name":"TimeLimit","value":"3"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0002-000000000003","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0002-000000000001",
+    "metadata":{"name":"Sample disconnect flow","description":"","type":"contactFlow"}
+}`
+
+func TestHangup(t *testing.T) {
+	sim := newTestSimulator(t, "Sample hangup flow", sampleHangupMain, sampleHangupDisconnect)
+	var cleanedUp int
+	err := sim.RegisterLambda("clean-up", func(context.Context, LambdaPayload) (struct{}, error) {
+		cleanedUp++
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering lambda: %v", err)
+	}
+
+	testCases := []struct {
+		desc       string
+		act        func(call *Call)
+		expReason  event.DisconnectReason
+		expCleanup int
+	}{
+		{
+			desc: "caller hangs up",
+			act: func(call *Call) {
+				call.Hangup()
+			},
+			expReason:  event.DisconnectCustomer,
+			expCleanup: 1,
+		},
+		{
+			desc: "caller hangs up twice",
+			act: func(call *Call) {
+				call.Hangup()
+				call.Hangup()
+			},
+			expReason:  event.DisconnectCustomer,
+			expCleanup: 1,
+		},
+		{
+			desc: "flow disconnects",
+			act: func(call *Call) {
+				call.Caller.I <- '1'
+			},
+			expReason:  event.DisconnectContactFlow,
+			expCleanup: 0,
+		},
+		{
+			desc: "call terminated",
+			act: func(call *Call) {
+				call.Terminate()
+			},
+			expReason:  event.DisconnectTelecom,
+			expCleanup: 0,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cleanedUp = 0
+			call := startTestCall(t, sim, CallConfig{})
+			evts := make(chan event.Event, 64)
+			call.Subscribe(evts)
+			if prompt := <-call.Caller.O; prompt != "Press 1 to finish." {
+				t.Fatalf("expected menu prompt but got '%s'", prompt)
+			}
+			tC.act(call)
+			var reason event.DisconnectReason
+			for evt := range evts {
+				switch evt.Type() {
+				case event.DisconnectType:
+					reason = evt.(event.DisconnectEvent).Reason
+				case event.BranchType:
+					// The menu is cut short by the caller hanging up, so none of its branches are taken.
+					if b := evt.(event.BranchEvent); tC.expReason == event.DisconnectCustomer && b.From == "00000000-0000-4000-0001-000000000002" {
+						t.Errorf("expected no branch to be taken from the menu but got '%s'", b.Label)
+					}
+				}
+			}
+			for prompt := range call.Caller.O {
+				t.Errorf("expected no more prompts to be heard but got '%s'", prompt)
+			}
+			if reason != tC.expReason {
+				t.Errorf("expected disconnect reason of '%s' but got '%s'", tC.expReason, reason)
+			}
+			if cleanedUp != tC.expCleanup {
+				t.Errorf("expected clean-up lambda to run %d times but it ran %d times", tC.expCleanup, cleanedUp)
+			}
+			if call.Err != nil {
+				t.Errorf("unexpected call error: %v", call.Err)
+			}
+		})
+	}
+}
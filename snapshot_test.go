@@ -0,0 +1,85 @@
+package simulator_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+)
+
+var sampleSnapshot = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0011-000000000001","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0011-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0011-000000000009"}],"parameters":[{"name":"Attribute","value":"gold","key":"tier","namespace":null}]},
+        {"id":"00000000-0000-4000-0011-000000000002","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-0011-000000000003"},{"condition":"Timeout","transition":"00000000-0000-4000-0011-000000000009"},{"condition":"NoMatch","transition":"00000000-0000-4000-0011-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-0011-000000000009"}],"parameters":[{"name":"Text","value":"Press 1 to continue."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-0011-000000000003","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-0011-000000000004"},{"condition":"Error","transition":"00000000-0000-4000-0011-000000000009"}],"parameters":[{"name":"Attribute","value":"platinum","key":"tier","namespace":null}]},
+        {"id":"00000000-0000-4000-0011-000000000004","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0011-000000000009"}],"parameters":[{"name":"Text","value":"Goodbye.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0011-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0011-000000000001",
+    "metadata":{"name":"Sample snapshot flow","description":"","type":"contactFlow"}
+}`
+
+func TestSnapshot(t *testing.T) {
+	sim := newTestSimulator(t, "Sample snapshot flow", sampleSnapshot)
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	call := startTestCall(t, sim, CallConfig{
+		Time: start,
+	})
+
+	// Poll the call throughout, as a UI would.
+	done := make(chan struct{})
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				call.Snapshot()
+				call.ContactRecord()
+			}
+		}
+	}()
+
+	if p := <-call.Caller.O; p != "Press 1 to continue." {
+		t.Fatalf("expected menu prompt but got '%s'", p)
+	}
+	snap := call.Snapshot()
+	if snap.Module == nil || snap.Module.ID != "00000000-0000-4000-0011-000000000002" {
+		t.Errorf("expected to be in the menu block but got %v", snap.Module)
+	}
+	if snap.Flow != "Sample snapshot flow" {
+		t.Errorf("expected flow of 'Sample snapshot flow' but got '%s'", snap.Flow)
+	}
+	if snap.ContactData["tier"] != "gold" {
+		t.Errorf("expected tier of 'gold' but got '%s'", snap.ContactData["tier"])
+	}
+	if snap.System[flow.SystemCustomerNumber] != "+447878123456" {
+		t.Errorf("expected customer number of '+447878123456' but got '%s'", snap.System[flow.SystemCustomerNumber])
+	}
+	if snap.Time.Before(start) || snap.Time.After(start.Add(time.Minute)) {
+		t.Errorf("expected time shortly after %v but got %v", start, snap.Time)
+	}
+
+	call.Caller.I <- '1'
+	if p := <-call.Caller.O; p != "Goodbye." {
+		t.Errorf("expected goodbye prompt but got '%s'", p)
+	}
+	if snap.ContactData["tier"] != "gold" {
+		t.Error("expected snapshot not to change as the call continues")
+	}
+	for range call.Caller.O {
+	}
+	close(done)
+	<-polled
+
+	snap = call.Snapshot()
+	if snap.Module != nil {
+		t.Errorf("expected no block once the call has ended but got %v", snap.Module)
+	}
+	if snap.ContactData["tier"] != "platinum" {
+		t.Errorf("expected tier of 'platinum' but got '%s'", snap.ContactData["tier"])
+	}
+}
@@ -0,0 +1,59 @@
+package simulator_test
+
+import (
+	"testing"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleSpeech = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000d-000000000001","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"CheckBalance","transition":"00000000-0000-4000-000d-000000000002"},{"condition":"Evaluate","conditionType":"Equals","conditionValue":"Speak","transition":"00000000-0000-4000-000d-000000000003"},{"condition":"NoMatch","transition":"00000000-0000-4000-000d-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-000d-000000000009"}],"parameters":[{"name":"Text","value":"How can I help?"},{"name":"TextToSpeechType","value":"text"},{"name":"BotName","value":"Banking"},{"name":"BotAlias","value":"$LATEST"},{"name":"BotRegion","value":"eu-west-2"}],"target":"Lex"},
+        {"id":"00000000-0000-4000-000d-000000000002","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000d-000000000009"}],"parameters":[{"name":"Text","value":"Your balance is 10 pounds.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000d-000000000003","type":"GetUserInput","branches":[{"condition":"Evaluate","conditionType":"Equals","conditionValue":"1","transition":"00000000-0000-4000-000d-000000000004"},{"condition":"Timeout","transition":"00000000-0000-4000-000d-000000000005"},{"condition":"NoMatch","transition":"00000000-0000-4000-000d-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-000d-000000000009"}],"parameters":[{"name":"Text","value":"Press 1 to speak to an agent."},{"name":"TextToSpeechType","value":"text"},{"name":"Timeout","value":"5"},{"name":"MaxDigits","value":"1"}],"target":"Digits"},
+        {"id":"00000000-0000-4000-000d-000000000004","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000d-000000000009"}],"parameters":[{"name":"Text","value":"Connecting you now.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000d-000000000005","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000d-000000000009"}],"parameters":[{"name":"Text","value":"Sorry, I didn't get that.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000d-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000d-000000000001",
+    "metadata":{"name":"Sample speech flow","description":"","type":"contactFlow"}
+}`
+
+func TestSpeech(t *testing.T) {
+	sim := newTestSimulator(t, "Sample speech flow", sampleSpeech)
+	sim.RegisterLexBot("Banking", func(utterance string) string {
+		switch utterance {
+		case "check my balance":
+			return "CheckBalance"
+		case "agent", "0":
+			return "Speak"
+		}
+		return ""
+	})
+
+	t.Run("intent", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("How can I help?")
+		expect.Caller().ToSay("check my balance")
+		expect.Prompt().ToEqual("Your balance is 10 pounds.")
+	})
+	t.Run("key press to bot", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("How can I help?")
+		expect.Caller().ToPress('0')
+		expect.Prompt().ToEqual("Press 1 to speak to an agent.")
+		expect.Caller().ToPress('1')
+		expect.Prompt().ToEqual("Connecting you now.")
+	})
+	t.Run("speech to digits", func(t *testing.T) {
+		call := startTestCall(t, sim, CallConfig{})
+		expect := flowtest.New(t, call)
+		expect.Prompt().ToEqual("How can I help?")
+		expect.Caller().ToSay("agent")
+		expect.Caller().ToSay("one")
+		expect.Prompt().ToEqual("Sorry, I didn't get that.")
+	})
+}
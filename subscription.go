@@ -0,0 +1,177 @@
+package simulator
+
+import (
+	"sync"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+// SubscribeOption changes how events are delivered to a channel given to Subscribe.
+type SubscribeOption func(*subscriber)
+
+// Buffered holds events for the channel until they can be written to it, so that the call never waits for the subscriber.
+// There is no limit to the number of events held.
+func Buffered() SubscribeOption {
+	return func(s *subscriber) {
+		s.queued = true
+		s.limit = 0
+	}
+}
+
+// DropOldest holds up to size events for the channel until they can be written to it, so that the call never waits for the subscriber.
+// When size events are already held, the oldest is dropped to make room. Subscription.Dropped counts the events dropped.
+// A size of less than 1 holds a single event.
+func DropOldest(size int) SubscribeOption {
+	if size < 1 {
+		size = 1
+	}
+	return func(s *subscriber) {
+		s.queued = true
+		s.limit = size
+	}
+}
+
+// OfType only delivers events of the given types.
+func OfType(types ...event.Type) SubscribeOption {
+	return func(s *subscriber) {
+		s.types = make(map[event.Type]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+}
+
+// Subscription is a channel registered to receive events from a call.
+type Subscription struct {
+	call *Call
+	sub  *subscriber
+}
+
+// Dropped gives the number of events dropped because the subscriber fell behind (see DropOldest).
+func (s *Subscription) Dropped() int {
+	s.sub.mutex.Lock()
+	defer s.sub.mutex.Unlock()
+	return s.sub.dropped
+}
+
+// Unsubscribe stops events being written to the subscription's channel (see Call.Unsubscribe).
+// Unlike Call.Unsubscribe, it also drops events still held for the channel after the call has ended.
+func (s *Subscription) Unsubscribe() {
+	s.call.Unsubscribe(s.sub.ch)
+	s.sub.stop()
+}
+
+// subscriber writes events to a channel given to Subscribe.
+// By default, each event is written as it happens, so the call waits until the channel takes it.
+// Queued subscribers hold events and write them from their own go routine instead.
+type subscriber struct {
+	ch     chan<- event.Event
+	types  map[event.Type]bool
+	queued bool
+	// limit is the most events a queued subscriber holds. Zero is no limit.
+	limit   int
+	mutex   sync.Mutex
+	queue   []event.Event
+	dropped int
+	closed  bool
+	wake    chan struct{}
+	// stopped is closed when the subscriber unsubscribes, so that the call stops waiting for it.
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newSubscriber(ch chan<- event.Event, opts []SubscribeOption) *subscriber {
+	s := &subscriber{ch: ch, stopped: make(chan struct{})}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.queued {
+		s.wake = make(chan struct{}, 1)
+		go s.deliver()
+	}
+	return s
+}
+
+// wants returns true if the subscriber takes events of the given type.
+func (s *subscriber) wants(evt event.Event) bool {
+	return s.types == nil || s.types[evt.Type()]
+}
+
+func (s *subscriber) send(evt event.Event) {
+	if !s.queued {
+		select {
+		case s.ch <- evt:
+		case <-s.stopped:
+		}
+		return
+	}
+	s.mutex.Lock()
+	if s.limit > 0 && len(s.queue) >= s.limit {
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.dropped++
+	}
+	s.queue = append(s.queue, evt)
+	s.mutex.Unlock()
+	s.notify()
+}
+
+// close closes the channel once any events held for it have been written.
+func (s *subscriber) close() {
+	if !s.queued {
+		close(s.ch)
+		return
+	}
+	s.mutex.Lock()
+	s.closed = true
+	s.mutex.Unlock()
+	s.notify()
+}
+
+// stop stops the call waiting to write to the subscriber.
+func (s *subscriber) stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopped)
+	})
+}
+
+func (s *subscriber) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver writes the events held by a queued subscriber to its channel, in order.
+// Once the subscriber unsubscribes, the events still held are dropped and the channel is closed.
+func (s *subscriber) deliver() {
+	defer close(s.ch)
+	for {
+		select {
+		case <-s.stopped:
+			s.mutex.Lock()
+			s.queue = nil
+			s.mutex.Unlock()
+			return
+		default:
+		}
+		s.mutex.Lock()
+		if len(s.queue) == 0 {
+			closed := s.closed
+			s.mutex.Unlock()
+			if closed {
+				return
+			}
+			<-s.wake
+			continue
+		}
+		evt := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.mutex.Unlock()
+		select {
+		case s.ch <- evt:
+		case <-s.stopped:
+		}
+	}
+}
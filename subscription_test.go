@@ -0,0 +1,141 @@
+package simulator_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+)
+
+func TestSubscribe(t *testing.T) {
+	// The endless loop flow holds for 100 blocks, playing a prompt every other block.
+	start := func(t *testing.T) *Call {
+		sim := newTestSimulator(t, "Sample endless loop flow", sampleEndlessLoop)
+		sim.SetLoopGuards(LoopGuards{MaxBlocks: 100})
+		return startTestCall(t, sim, CallConfig{})
+	}
+	// collect reads events from a channel until it is closed.
+	collect := func(evts <-chan event.Event) <-chan []event.Event {
+		out := make(chan []event.Event, 1)
+		go func() {
+			got := []event.Event{}
+			for evt := range evts {
+				got = append(got, evt)
+			}
+			out <- got
+		}()
+		return out
+	}
+
+	t.Run("buffered", func(t *testing.T) {
+		call := start(t)
+		blocking := make(chan event.Event)
+		blockingGot := collect(blocking)
+		call.Subscribe(blocking)
+		// Nothing reads this channel until the call has ended.
+		buffered := make(chan event.Event)
+		call.Subscribe(buffered, Buffered())
+		for range call.Caller.O {
+		}
+		bufferedGot := collect(buffered)
+		exp, got := <-blockingGot, <-bufferedGot
+		if len(got) != len(exp) {
+			t.Fatalf("expected %d events but got %d", len(exp), len(got))
+		}
+		for i := range exp {
+			if !reflect.DeepEqual(got[i], exp[i]) {
+				t.Fatalf("expected event %d to be %v but got %v", i, exp[i], got[i])
+			}
+		}
+	})
+	t.Run("drop oldest", func(t *testing.T) {
+		call := start(t)
+		all := make(chan event.Event)
+		allGot := collect(all)
+		call.Subscribe(all)
+		slow := make(chan event.Event)
+		sub := call.Subscribe(slow, DropOldest(10))
+		for range call.Caller.O {
+		}
+		// One event may already be waiting to be written when the call ends, on top of the 10 held.
+		slowGot := <-collect(slow)
+		exp := <-allGot
+		if len(slowGot) < 10 || len(slowGot) > 11 {
+			t.Fatalf("expected 10 or 11 events but got %d", len(slowGot))
+		}
+		if len(slowGot)+sub.Dropped() != len(exp) {
+			t.Errorf("expected %d events to be dropped but %d were", len(exp)-len(slowGot), sub.Dropped())
+		}
+		for i, evt := range slowGot[len(slowGot)-10:] {
+			if want := exp[len(exp)-10+i]; !reflect.DeepEqual(evt, want) {
+				t.Errorf("expected the most recent events to be kept but event %d was %v and not %v", i, evt, want)
+			}
+		}
+	})
+	t.Run("filter", func(t *testing.T) {
+		call := start(t)
+		prompts := make(chan event.Event)
+		promptsGot := collect(prompts)
+		call.Subscribe(prompts, OfType(event.PromptType, event.DisconnectType))
+		for range call.Caller.O {
+		}
+		got := <-promptsGot
+		if len(got) != 51 {
+			t.Errorf("expected 50 prompts and a disconnect but got %d events", len(got))
+		}
+		for _, evt := range got[:len(got)-1] {
+			if evt.Type() != event.PromptType {
+				t.Fatalf("expected only prompt events but got %s", evt.Type())
+			}
+		}
+	})
+	t.Run("unsubscribe", func(t *testing.T) {
+		call := start(t)
+		evts := make(chan event.Event)
+		sub := call.Subscribe(evts)
+		<-evts
+		sub.Unsubscribe()
+		if _, ok := <-evts; ok {
+			t.Error("expected channel to be closed by unsubscribing")
+		}
+		// The call goes on without the subscriber.
+		for range call.Caller.O {
+		}
+		call.Unsubscribe(evts)
+	})
+	t.Run("unsubscribe without reading", func(t *testing.T) {
+		call := start(t)
+		evts := make(chan event.Event)
+		sub := call.Subscribe(evts, Buffered())
+		for range call.Caller.O {
+		}
+		sub.Unsubscribe()
+		// Only an event already being written when it unsubscribed can get through. The rest are dropped.
+		timeout := time.After(time.Second)
+		for n := 0; ; n++ {
+			select {
+			case _, ok := <-evts:
+				if !ok {
+					return
+				}
+				if n > 0 {
+					t.Fatal("expected events held for the channel to be dropped by unsubscribing")
+				}
+			case <-timeout:
+				t.Fatal("expected channel to be closed by unsubscribing")
+			}
+		}
+	})
+	t.Run("after end", func(t *testing.T) {
+		call := start(t)
+		for range call.Caller.O {
+		}
+		evts := make(chan event.Event, 1)
+		call.Subscribe(evts, Buffered())
+		if _, ok := <-evts; ok {
+			t.Error("expected channel to be closed when subscribing to an ended call")
+		}
+	})
+}
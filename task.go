@@ -0,0 +1,127 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/edwardbrowncross/amazon-connect-simulator/flow"
+	"github.com/edwardbrowncross/amazon-connect-simulator/module"
+)
+
+// TaskConfig describes a task, in the manner of the StartTaskContact API.
+type TaskConfig struct {
+	// FlowName is the name of the flow that handles the task.
+	FlowName string
+	// Name is the name of the task, as shown to the agent.
+	Name string
+	// Description is an optional longer description of the task.
+	Description string
+	// References are links to be shown to the agent, keyed by name.
+	References map[string]string
+	// Attributes are contact attributes set before the flow starts.
+	Attributes map[string]string
+	// ScheduledTime, if given, is when the task starts. The task's clock starts at this time.
+	ScheduledTime time.Time
+	// Time is the time the task is created (for in-hours check).
+	Time time.Time
+}
+
+// taskDetails are the parts of a task contact that are not held in the flow's state.
+type taskDetails struct {
+	references       map[string]string
+	scheduled        time.Time
+	relatedContactID string
+}
+
+// StartTask creates a new task asynchronously and returns a Call object for interacting with it.
+// The task's Channel is TASK and its InitiationMethod is API.
+// A task has no customer: prompts go unheard and blocks that take input time out.
+func (cs *Simulator) StartTask(config TaskConfig) (*Call, error) {
+	sc := &simulatorConnector{cs}
+	c, start, err := sc.newTask(config)
+	if err != nil {
+		return nil, err
+	}
+	c.System[flow.SystemInitiationMethod] = "API"
+	c.start(sc, start)
+	return c, nil
+}
+
+// OnTaskCreated registers a function to be called with each task created by a Create Task block.
+// It is called before the task's flow starts, so events from the task can be subscribed to without missing any.
+func (cs *Simulator) OnTaskCreated(fn func(task *Call)) {
+	cs.onTaskCreated = fn
+}
+
+// newTask builds a task contact without starting it.
+// It returns the start of the task's flow.
+func (cs *simulatorConnector) newTask(config TaskConfig) (*Call, flow.ModuleID, error) {
+	f, ok := cs.flows[config.FlowName]
+	if !ok {
+		return nil, "", fmt.Errorf("flow not found: %s. Load the flow with LoadFlow before calling this method", config.FlowName)
+	}
+	if config.Name == "" {
+		return nil, "", errors.New("a name must be provided in order to start a task")
+	}
+	t := config.Time
+	if !config.ScheduledTime.IsZero() {
+		t = config.ScheduledTime
+	}
+	c := newCall(CallConfig{
+		Time:       t,
+		Attributes: config.Attributes,
+		Channel:    flow.ChannelTask,
+	}, cs)
+	c.System[flow.SystemTaskName] = config.Name
+	c.System[flow.SystemTaskDescription] = config.Description
+	c.task = &taskDetails{
+		references: map[string]string{},
+		scheduled:  config.ScheduledTime,
+	}
+	for k, v := range config.References {
+		c.task.references[k] = v
+	}
+	return c, f.Start, nil
+}
+
+// CreateTask starts a task contact on behalf of a Create Task block.
+// The task takes a copy of this contact's attributes and is related to this contact. It is bound to the same context.
+func (s *callConnector) CreateTask(task module.Task) (contactID string, err error) {
+	c, start, err := s.newTask(TaskConfig{
+		FlowName:      task.FlowName,
+		Name:          task.Name,
+		Description:   task.Description,
+		References:    task.References,
+		Attributes:    s.ContactData,
+		ScheduledTime: task.ScheduledTime,
+		Time:          s.now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	c.System[flow.SystemInitiationMethod] = "FLOW"
+	c.task.relatedContactID = s.System[flow.SystemContactID]
+	c.ctx = s.ctx
+	if s.onTaskCreated != nil {
+		s.onTaskCreated(c)
+	}
+	contactID = c.System[flow.SystemContactID]
+	c.start(s.simulatorConnector, start)
+	return contactID, nil
+}
+
+// contactRecordReferences lists a task's references in the form of the contact trace record, in name order.
+func (d *taskDetails) contactRecordReferences() []ContactRecordReference {
+	names := make([]string, 0, len(d.references))
+	for k := range d.references {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	r := make([]ContactRecordReference, len(names))
+	for i, n := range names {
+		r[i] = ContactRecordReference{Name: n, Type: "URL", Value: d.references[n]}
+	}
+	return r
+}
@@ -0,0 +1,113 @@
+package simulator_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleTaskFlow = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000c-000000000001","type":"SetQueue","branches":[{"condition":"Success","transition":"00000000-0000-4000-000c-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-000c-000000000009"}],"parameters":[{"name":"Queue","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/queue/ffffffff-0000-4000-0000-ffffffff0003","namespace":null,"resourceName":"Callbacks"}]},
+        {"id":"00000000-0000-4000-000c-000000000002","type":"Transfer","branches":[{"condition":"AtCapacity","transition":"00000000-0000-4000-000c-000000000009"},{"condition":"Error","transition":"00000000-0000-4000-000c-000000000009"}],"parameters":[],"target":"Queue"},
+        {"id":"00000000-0000-4000-000c-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000c-000000000001",
+    "metadata":{"name":"Sample task flow","description":"","type":"contactFlow"}
+}`
+
+var sampleCreateTask = `{
+    "modules":[
+        {"id":"00000000-0000-4000-000c-000000000011","type":"SetAttributes","branches":[{"condition":"Success","transition":"00000000-0000-4000-000c-000000000012"},{"condition":"Error","transition":"00000000-0000-4000-000c-000000000012"}],"parameters":[{"name":"Attribute","value":"Customer Number","key":"caller","namespace":"System"}]},
+        {"id":"00000000-0000-4000-000c-000000000012","type":"CreateTask","branches":[{"condition":"Success","transition":"00000000-0000-4000-000c-000000000013"},{"condition":"Error","transition":"00000000-0000-4000-000c-000000000019"}],"parameters":[{"name":"ContactFlowId","value":"arn:aws:connect:eu-west-2:456789012345:instance/ffffffff-ffff-4000-ffff-ffffffffffff/contact-flow/00000000-0000-4000-000c-000000000000","resourceName":"Sample task flow"},{"name":"Name","value":"Call back $.Attributes.caller"},{"name":"Reference","key":"account","value":"https://example.com/accounts/123"}]},
+        {"id":"00000000-0000-4000-000c-000000000013","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-000c-000000000019"}],"parameters":[{"name":"Text","value":"We will call you back.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-000c-000000000019","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-000c-000000000011",
+    "metadata":{"name":"Sample create task flow","description":"","type":"contactFlow"}
+}`
+
+func TestStartTask(t *testing.T) {
+	sim := New()
+	if err := sim.LoadFlowJSON([]byte(sampleTaskFlow)); err != nil {
+		t.Fatalf("unexpected error parsing flow: %v", err)
+	}
+
+	t.Run("bad config", func(t *testing.T) {
+		if _, err := sim.StartTask(TaskConfig{FlowName: "Missing flow", Name: "Call back"}); err == nil {
+			t.Error("expected an error starting a task with an unknown flow but got none")
+		}
+		if _, err := sim.StartTask(TaskConfig{FlowName: "Sample task flow"}); err == nil {
+			t.Error("expected an error starting a task with no name but got none")
+		}
+	})
+	t.Run("api", func(t *testing.T) {
+		scheduled := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+		task, err := sim.StartTask(TaskConfig{
+			FlowName:      "Sample task flow",
+			Name:          "Call back",
+			Description:   "Customer asked for a call back",
+			References:    map[string]string{"account": "https://example.com/accounts/123"},
+			Attributes:    map[string]string{"caller": "+447878123456"},
+			ScheduledTime: scheduled,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error starting task: %v", err)
+		}
+		expect := flowtest.New(t, task)
+		expect.Transfer().ToQueue("Callbacks")
+		for range task.Caller.O {
+		}
+		r := task.ContactRecord()
+		if r.Channel != "TASK" || r.InitiationMethod != "API" {
+			t.Errorf("expected channel TASK and initiation method API but got %s and %s", r.Channel, r.InitiationMethod)
+		}
+		if r.Name != "Call back" || r.Description != "Customer asked for a call back" {
+			t.Errorf("expected task name and description to be recorded but got '%s' and '%s'", r.Name, r.Description)
+		}
+		if len(r.References) != 1 || r.References[0].Name != "account" || r.References[0].Value != "https://example.com/accounts/123" {
+			t.Errorf("expected account reference but got %v", r.References)
+		}
+		if r.ScheduledTimestamp == nil || !r.ScheduledTimestamp.Equal(scheduled) || !r.InitiationTimestamp.Equal(scheduled) {
+			t.Errorf("expected task to be scheduled for %v but got %v", scheduled, r.ScheduledTimestamp)
+		}
+		if r.Attributes["caller"] != "+447878123456" {
+			t.Errorf("expected caller attribute to be set but got %v", r.Attributes)
+		}
+	})
+}
+
+func TestCreateTask(t *testing.T) {
+	sim := newTestSimulator(t, "Sample create task flow", sampleTaskFlow, sampleCreateTask)
+	tasks := make(chan *Call, 1)
+	taskExpect := make(chan *flowtest.Expect, 1)
+	sim.OnTaskCreated(func(task *Call) {
+		taskExpect <- flowtest.New(t, task)
+		tasks <- task
+	})
+
+	call := startTestCall(t, sim, CallConfig{})
+	expect := flowtest.New(t, call)
+	expect.Task().
+		InFlow("Sample task flow").
+		WithReference("account", "https://example.com/accounts/123").
+		ToBeCreated("Call back +447878123456")
+	expect.Prompt().ToEqual("We will call you back.")
+
+	task := <-tasks
+	(<-taskExpect).Transfer().ToQueue("Callbacks")
+	for range task.Caller.O {
+	}
+	r := task.ContactRecord()
+	if r.Channel != "TASK" || r.InitiationMethod != "FLOW" {
+		t.Errorf("expected channel TASK and initiation method FLOW but got %s and %s", r.Channel, r.InitiationMethod)
+	}
+	if exp := call.ContactRecord().ContactID; r.RelatedContactID != exp {
+		t.Errorf("expected task to be related to contact %s but got %s", exp, r.RelatedContactID)
+	}
+	if r.Attributes["caller"] != "+447878123456" {
+		t.Errorf("expected attributes to be copied to the task but got %v", r.Attributes)
+	}
+}
@@ -0,0 +1,77 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/edwardbrowncross/amazon-connect-simulator"
+	"github.com/edwardbrowncross/amazon-connect-simulator/event"
+	"github.com/edwardbrowncross/amazon-connect-simulator/flowtest"
+)
+
+var sampleLambdaTimeout = `{
+    "modules":[
+        {"id":"00000000-0000-4000-0013-000000000001","type":"InvokeExternalResource","branches":[{"condition":"Success","transition":"00000000-0000-4000-0013-000000000002"},{"condition":"Error","transition":"00000000-0000-4000-0013-000000000003"}],"parameters":[{"name":"FunctionArn","value":"arn:aws:lambda:eu-west-2:456789012345:function:balance-lookup"},{"name":"TimeLimit","value":"1"}],"target":"Lambda"},
+        {"id":"00000000-0000-4000-0013-000000000002","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0013-000000000009"}],"parameters":[{"name":"Text","value":"Your balance is $.External.balance.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0013-000000000003","type":"PlayPrompt","branches":[{"condition":"Success","transition":"00000000-0000-4000-0013-000000000009"}],"parameters":[{"name":"Text","value":"Sorry, we can't find your balance right now.","namespace":null},{"name":"TextToSpeechType","value":"text"}]},
+        {"id":"00000000-0000-4000-0013-000000000009","type":"Disconnect","branches":[],"parameters":[]}
+    ],
+    "start":"00000000-0000-4000-0013-000000000001",
+    "metadata":{"name":"Sample lambda timeout flow","description":"","type":"contactFlow"}
+}`
+
+func TestLambdaTimeout(t *testing.T) {
+	type balance struct {
+		Balance string `json:"balance"`
+	}
+	start := func(t *testing.T, delay time.Duration) *Call {
+		sim := newTestSimulator(t, "Sample lambda timeout flow", sampleLambdaTimeout)
+		sim.RegisterLambda("balance-lookup", func(ctx context.Context, in LambdaPayload) (balance, error) {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return balance{}, ctx.Err()
+			}
+			return balance{"ten pounds"}, nil
+		})
+		return startTestCall(t, sim, CallConfig{})
+	}
+
+	t.Run("in time", func(t *testing.T) {
+		call := start(t, 0)
+		expect := flowtest.New(t, call)
+		expect.Lambda().Never().ToTimeOut()
+		expect.Lambda().ToSucceed()
+		expect.Prompt().ToEqual("Your balance is ten pounds.")
+	})
+	t.Run("stalled", func(t *testing.T) {
+		call := start(t, time.Minute)
+		evts := make(chan event.Event, 64)
+		call.Subscribe(evts)
+		expect := flowtest.New(t, call)
+		began := time.Now()
+		expect.Lambda().WithARN("balance-lookup").ToTimeOut()
+		expect.Prompt().ToEqual("Sorry, we can't find your balance right now.")
+		if d := time.Since(began); d < time.Second || d > 2*time.Second {
+			t.Errorf("expected lambda to be abandoned after about a second but it took %v", d)
+		}
+		for range call.Caller.O {
+		}
+		var timeout *event.LambdaTimeoutEvent
+		for evt := range evts {
+			if e, ok := evt.(event.LambdaTimeoutEvent); ok {
+				timeout = &e
+			}
+		}
+		if timeout == nil {
+			t.Fatal("expected a lambda timeout event")
+		}
+		if timeout.Timeout != time.Second {
+			t.Errorf("expected timeout of 1s but got %v", timeout.Timeout)
+		}
+		if call.Err != nil {
+			t.Errorf("expected call to end without error but got %v", call.Err)
+		}
+	})
+}